@@ -2,9 +2,12 @@ package Arguments
 
 import (
 	"SugarFree/Packages/Colors"
+	"SugarFree/Packages/Reduce"
 	"fmt"
 	"log"
 	"os"
+	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -63,15 +66,43 @@ func init() {
 
 	// Add flags to the 'info' command.
 	infoArgument.Flags().SortFlags = true
-	infoArgument.Flags().StringP("file", "f", "", "Set input file")
+	infoArgument.Flags().StringSliceP("file", "f", nil, "Set input files, directories or glob patterns")
+	infoArgument.Flags().BoolP("recursive", "r", false, "Scan directories recursively")
+	infoArgument.Flags().Int("workers", runtime.NumCPU(), "Set number of concurrent analysis workers")
+	infoArgument.Flags().String("sort", "name", "Sort batch summary by key (i.e., name, size, entropy, max-section)")
+	infoArgument.Flags().BoolP("detail", "d", false, "Show per-file detail after the batch summary")
 	infoArgument.Flags().StringP("output", "o", "", "Save results to output file")
+	infoArgument.Flags().String("format", "text", "Set report format (i.e., text, json, csv, markdown, html)")
+	infoArgument.Flags().BoolP("profile", "p", false, "Enable sliding-window entropy profile")
+	infoArgument.Flags().Int64P("window", "w", 4096, "Set profile window size in bytes")
+	infoArgument.Flags().Int64("step", 0, "Set profile step size in bytes (default window size)")
+	infoArgument.Flags().String("profile-output", "", "Export entropy profile to CSV file")
+	infoArgument.Flags().BoolP("graph", "g", false, "Enable entropy profile graph")
+	infoArgument.Flags().Float64("threshold", Colors.EntropyThreshold, "Set entropy threshold line for the graph")
+	infoArgument.Flags().Bool("raw", false, "Analyze every input as a raw blob (e.g., shellcode)")
+	infoArgument.Flags().Bool("stats", false, "Add byte histogram, chi-square, mean, serial correlation and Monte Carlo pi statistics")
+	infoArgument.Flags().Bool("metrics", false, "Add conditional, Rényi and min-entropy and DEFLATE compression ratio metrics")
+	infoArgument.Flags().Bool("exclude-overlay", false, "Exclude the overlay from the overall entropy")
+	infoArgument.Flags().Float64("fail-above", 0, "Exit with code 2 if any overall entropy is above this value (5 if any input is missing or cannot be analyzed)")
+	infoArgument.Flags().Float64("fail-section-above", 0, "Exit with code 3 if any section entropy is above this value, pseudo-regions excluded (4 if both gates trip)")
 
 	// Add flags to the 'free' command.
 	freeArgument.Flags().SortFlags = true
 	freeArgument.Flags().StringP("file", "f", "", "Set input file")
 	freeArgument.Flags().Float64P("target", "t", 4.6, "Set target entropy value to achieve")
-	freeArgument.Flags().StringP("strategy", "s", "zero", "Set strategy to apply (i.e., zero, word)")
+	freeArgument.Flags().StringP("strategy", "s", "zero", fmt.Sprintf("Set strategy to apply (i.e., %s)", strings.Join(Reduce.Names(), ", ")))
+	freeArgument.Flags().Bool("list-strategies", false, "List the available strategies and exit")
+	freeArgument.Flags().String("pattern", "", "Set hex fill pattern of the pattern strategy (i.e., 90)")
+	freeArgument.Flags().String("pattern-file", "", "Set file holding the fill pattern of the pattern strategy")
+	freeArgument.MarkFlagsMutuallyExclusive("pattern", "pattern-file")
+	freeArgument.Flags().String("wordlist", "", "Set word list file of the word strategy, one word per line")
+	freeArgument.Flags().String("separator", "none", "Set separator between words of the word strategy (i.e., none, space, newline)")
 	freeArgument.Flags().BoolP("graph", "g", false, "Enable entropy graph")
+	freeArgument.Flags().BoolP("exact", "e", false, "Solve the exact padding size for the target and write a single output")
+	freeArgument.Flags().String("out-dir", "", "Set output directory for generated files")
+	freeArgument.Flags().StringP("output", "o", "", "Set output path of the final file")
+	freeArgument.Flags().Bool("keep-stages", false, "Keep the file of every reduction stage")
+	freeArgument.Flags().Bool("dry-run", false, "Predict stage entropies, final size and growth without writing files")
 }
 
 // ShowVersion function
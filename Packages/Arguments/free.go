@@ -5,11 +5,16 @@ import (
 	"SugarFree/Packages/Colors"
 	"SugarFree/Packages/Reduce"
 	"SugarFree/Packages/Utils"
+	"SugarFree/Packages/WordList"
+	"bytes"
 	"fmt"
 	"image/color"
-	"io/ioutil"
+	"io"
 	"log"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,18 +25,55 @@ import (
 	"gonum.org/v1/plot/vg"
 )
 
+// Limits of the exact reduction padding
+const (
+	maxPaddingSize   = 1 << 30 // Largest solved padding size in bytes
+	paddingChunkSize = 1 << 20 // Padding generated at once while streaming
+	maxPaddingTopUps = 5       // Extra rounds when generated padding misses the target
+)
+
 // StageData represents data for each reduction stage
 type StageData struct {
 	stage   int
 	entropy float64
 }
 
+// freeOptions holds where the free command writes its files
+type freeOptions struct {
+	outDir     string // Directory for stage files, the final file and the graph
+	output     string // Path of the final file
+	keepStages bool   // Keep every stage file instead of the final one only
+}
+
+// artifactPath function
+// artifactPath places a file in the output directory when one is set,
+// otherwise it stays next to the input as the name already includes its
+// directory.
+func (o freeOptions) artifactPath(name string) string {
+	if o.outDir == "" {
+		return name
+	}
+
+	return filepath.Join(o.outDir, filepath.Base(name))
+}
+
+// finalPath function
+// finalPath returns the path of the final file, named after its entropy
+// unless an explicit output path is set.
+func (o freeOptions) finalPath(fileName string, fileExtension string, entropy float64) string {
+	if o.output != "" {
+		return o.output
+	}
+
+	return o.artifactPath(Utils.BuildNewName(fileName, fileExtension, strconv.FormatFloat(entropy, 'f', 5, 64)))
+}
+
 // freeArgument represents the 'free' command in the CLI.
 var freeArgument = &cobra.Command{
 	// Use defines how the command should be called.
 	Use:          "free",
 	Short:        "Free command",
-	Long:         "Lowers the overall entropy of a PE file",
+	Long:         "Lowers the overall entropy of a PE, ELF, Mach-O or raw file",
 	SilenceUsage: true,
 	Aliases:      []string{"FREE", "Free"},
 
@@ -56,21 +98,80 @@ var freeArgument = &cobra.Command{
 		file, _ := cmd.Flags().GetString("file")
 		target, _ := cmd.Flags().GetFloat64("target")
 		graph, _ := cmd.Flags().GetBool("graph")
-		strategy, _ := cmd.Flags().GetString("strategy")
+		strategyName, _ := cmd.Flags().GetString("strategy")
+		exact, _ := cmd.Flags().GetBool("exact")
+		outDir, _ := cmd.Flags().GetString("out-dir")
+		output, _ := cmd.Flags().GetString("output")
+		keepStages, _ := cmd.Flags().GetBool("keep-stages")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		listStrategies, _ := cmd.Flags().GetBool("list-strategies")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		patternFile, _ := cmd.Flags().GetString("pattern-file")
+		wordlist, _ := cmd.Flags().GetString("wordlist")
+		separator, _ := cmd.Flags().GetString("separator")
+
+		// Check if the list strategies flag is enabled
+		if listStrategies {
+			// Call function named printStrategies
+			printStrategies()
+			return nil
+		}
+
+		// A fill pattern selects the pattern strategy unless another one is set
+		if pattern != "" || patternFile != "" {
+			if !cmd.Flags().Changed("strategy") {
+				strategyName = "pattern"
+			} else if !strings.EqualFold(strategyName, "pattern") {
+				logger.Fatal("Error: --pattern and --pattern-file require the pattern strategy\n\n")
+			}
+
+			// Call function named registerPattern
+			if err := registerPattern(pattern, patternFile); err != nil {
+				logger.Fatal("Error: ", err)
+			}
+		}
+
+		// A word list or separator selects the word strategy unless another one is set
+		if wordlist != "" || cmd.Flags().Changed("separator") {
+			if !cmd.Flags().Changed("strategy") {
+				strategyName = "word"
+			} else if !strings.EqualFold(strategyName, "word") {
+				logger.Fatal("Error: --wordlist and --separator require the word strategy\n\n")
+			}
+
+			// Call function named registerWordList
+			if err := registerWordList(wordlist, separator); err != nil {
+				logger.Fatal("Error: ", err)
+			}
+		}
+
+		// Call function named NewStrategy
+		strategy, err := Reduce.NewStrategy(strategyName)
+		if err != nil {
+			logger.Fatal("Error: ", err)
+		}
 
 		// Check if the file flag is empty
 		if file == "" {
 			logger.Fatal("Error: Input file is missing. Please provide it to continue...\n\n")
 		}
 
+		// Create the output directory, a dry run writes nothing
+		options := freeOptions{outDir: outDir, output: output, keepStages: keepStages}
+		if outDir != "" && !dryRun {
+			if err := os.MkdirAll(outDir, 0755); err != nil {
+				logger.Fatal("Error creating output directory: ", err)
+			}
+		}
+
 		// Record start time for performance measurement
 		reductionStartTime := time.Now()
 
 		// Get the current date and time
 		getDateTime := time.Now().Format("2006-01-02 15:04:05")
 
-		fmt.Printf("[*] Starting PE entropy reduction on %s\n\n", Colors.BoldWhite(getDateTime))
-		fmt.Printf("[*] Applied Strategy: %s\n\n", Colors.BoldBlue(strings.ToUpper(strategy)))
+		fmt.Printf("[*] Starting entropy reduction on %s\n\n", Colors.BoldWhite(getDateTime))
+		fmt.Printf("[*] Applied Strategy: %s\n\n", Colors.BoldBlue(strings.ToUpper(strategy.Name())))
 
 		// Get absolute file path
 		filePath, err := Utils.GetAbsolutePath(file)
@@ -87,126 +188,184 @@ var freeArgument = &cobra.Command{
 		// Get filename and extension
 		fileName, fileExtension := Utils.SplitFileName(file)
 
-		fmt.Printf("[+] Analyzing PE File: %s\n", Colors.BoldCyan(file))
+		// Open original binary data
+		inputFile, err := os.Open(filePath)
+		if err != nil {
+			logger.Fatal("Error reading input file: ", err)
+		}
+
+		// Get file information
+		fileInfo, err := inputFile.Stat()
+		if err != nil {
+			inputFile.Close()
+			logger.Fatal("Error: ", err)
+		}
+
+		// Call function named OpenBinary, only the bytes are needed so a file
+		// that fails to parse is reduced as a raw blob
+		binary, err := Calculate.OpenBinary(inputFile, fileInfo.Size())
+		if err != nil {
+			logger.Printf("Warning: %v, treating the file as a raw blob\n", err)
+			binary = Calculate.OpenRawBinary(inputFile, fileInfo.Size())
+		}
+		format := binary.Format()
+
+		fmt.Printf("[+] Analyzing %s File: %s\n", format, Colors.BoldCyan(file))
 		fmt.Printf("[+] Initial File Size: %s KB\n", Colors.BoldYellow(fileSize))
+		fmt.Printf("[+] Regions Found: %s\n", Colors.BoldYellow(len(binary.Regions())))
 
-		// Read original binary data
-		originalData, err := ioutil.ReadFile(file)
+		// Stream the byte histogram once and update it as padding is appended
+		histogram, err := Calculate.HistogramFromReader(io.NewSectionReader(binary.ReaderAt(), 0, binary.Size()))
+		inputFile.Close()
 		if err != nil {
-			fmt.Printf("Error reading input file: %v\n", err)
-			os.Exit(1)
+			logger.Fatal("Error reading input file: ", err)
 		}
 
 		// Calculate initial entropy
-		initialEntropy := Calculate.CalculateFullEntropy(originalData)
+		initialEntropy := histogram.Entropy()
+
+		// Display initial overall entropy
+		fmt.Printf("[+] Initial Overall %s Entropy: %s\n", format, Colors.CalculateColor2Entropy(initialEntropy))
+
+		// Call function named printPaddingDistribution
+		printPaddingDistribution(strategy.Distribution())
 
-		// Display initial overall PE entropy
-		fmt.Printf("[+] Initial Overall PE Entropy: %s\n", Colors.CalculateColor2Entropy(initialEntropy))
+		// Check if the dry run flag is enabled
+		if dryRun {
+			// Call function named dryRunReduction
+			dryRunReduction(format, histogram, fileInfo.Size(), target, strategy, exact)
+
+			// Calculate the duration
+			fmt.Printf("\n[*] Completed in: %s\n\n", Colors.BoldWhite(time.Since(reductionStartTime)))
+
+			return nil
+		}
 
 		// Create a slice to store entropy values for each stage
 		stageData := []StageData{
 			{0, initialEntropy}, // Include initial entropy as stage 0
 		}
 
-		// Copy the original data
-		modifiedData := make([]byte, len(originalData))
-		copy(modifiedData, originalData)
+		// Seed the padding generator
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+		// Only the padding is kept in memory, the original data is streamed from disk
+		var padding []byte
 
 		// Add variables to track progress
 		currentEntropy := initialEntropy
 		iterationCount := 0
 		lastEntropy := currentEntropy
 		stuckCount := 0
-		maxIterations := 10 // Maximum number of iterations to prevent infinite loops
-
-		// Loop until we reach target entropy or can't reduce further
-		for currentEntropy > target && iterationCount < maxIterations {
-			// Call function named ApplyStrategy
-			modifiedData = Reduce.ApplyStrategy(modifiedData, 60000, strategy)
-
-			// Calculate new entropy
-			currentEntropy = Calculate.CalculateFullEntropy(modifiedData)
-
-			// Calculate reduction percentages
-			totalReductionPercentage := ((initialEntropy - currentEntropy) / initialEntropy) * 100
-			stageReductionPercentage := ((lastEntropy - currentEntropy) / lastEntropy) * 100
-
-			// Calculate and display progress for this iteration
-			iterationCount++
 
-			// Store stage data for graphing
-			stageData = append(stageData, StageData{
-				stage:   iterationCount,
-				entropy: currentEntropy,
-			})
+		// If exact flag is enabled
+		if exact {
+			// Call function named exactReduction
+			stageData = append(stageData, exactReduction(filePath, format, histogram, initialEntropy, target, strategy, rng, fileName, fileExtension, options)...)
+		} else {
+			// Loop until we reach target entropy or can't reduce further
+			for currentEntropy > target && iterationCount < Reduce.MaxStages {
+				// Call function named Generate
+				stagePadding := strategy.Generate(Reduce.StageSize, rng)
+				padding = append(padding, stagePadding...)
+
+				// Calculate new entropy from the appended bytes only
+				histogram.Add(stagePadding)
+				currentEntropy = histogram.Entropy()
+
+				// Calculate reduction percentages
+				totalReductionPercentage := ((initialEntropy - currentEntropy) / initialEntropy) * 100
+				stageReductionPercentage := ((lastEntropy - currentEntropy) / lastEntropy) * 100
+
+				// Calculate and display progress for this iteration
+				iterationCount++
+
+				// Store stage data for graphing
+				stageData = append(stageData, StageData{
+					stage:   iterationCount,
+					entropy: currentEntropy,
+				})
+
+				// The stage file size follows from the padding, files are only written on request
+				newFileSize := float64(fileInfo.Size()+int64(len(padding))) / 1024.0
+
+				if iterationCount == 1 {
+					// For first stage, show entropy and current reduction percentage
+					fmt.Printf("\n[+] Stage %d Reduction - Overall %s Entropy: %s\n",
+						iterationCount,
+						format,
+						Colors.CalculateColor2Entropy(currentEntropy))
+					fmt.Printf("[+] Stage %d Current Reduction Percentage: %s%%\n",
+						iterationCount,
+						Colors.BoldMagenta(fmt.Sprintf("%.2f", stageReductionPercentage)))
+					fmt.Printf("[+] Stage %d File Size: %s KB\n",
+						iterationCount,
+						Colors.BoldYellow(newFileSize))
+				} else {
+					// For subsequent stages, show all messages in desired order
+					fmt.Printf("\n[+] Stage %d Reduction - Overall %s Entropy: %s\n",
+						iterationCount,
+						format,
+						Colors.CalculateColor2Entropy(currentEntropy))
+					fmt.Printf("[+] Stage %d Current Reduction Percentage: %s%%\n",
+						iterationCount,
+						Colors.BoldMagenta(fmt.Sprintf("%.2f", stageReductionPercentage)))
+					fmt.Printf("[+] Stage %d File Size: %s KB\n",
+						iterationCount,
+						Colors.BoldYellow(newFileSize))
+					fmt.Printf("[+] Stage %d Total Reduction Percentage: %s%%\n",
+						iterationCount,
+						Colors.BoldBlue(fmt.Sprintf("%.2f", totalReductionPercentage)))
+				}
 
-			// Convert current entropy to string for filename
-			stageEntropy := strconv.FormatFloat(currentEntropy, 'f', 5, 64)
+				// Check if the keep stages flag is enabled
+				if options.keepStages {
+					// Build new filename for this stage
+					stageFileName := options.artifactPath(Utils.BuildNewName(fileName, fileExtension, strconv.FormatFloat(currentEntropy, 'f', 5, 64)))
 
-			// Build new filename for this stage
-			stageFileName := Utils.BuildNewName(fileName, fileExtension, stageEntropy)
+					// Write stage data to output file
+					if err := Utils.WritePaddedFile(stageFileName, filePath, bytes.NewReader(padding)); err != nil {
+						logger.Fatalf("Error writing stage file: %v\n", err)
+					}
 
-			// Write stage data to output file
-			if err := ioutil.WriteFile(stageFileName, modifiedData, 0644); err != nil {
-				fmt.Printf("[!] Error writing stage file: %v\n", err)
-				continue
-			}
+					// Get absolute path for stage file
+					stageFileName, err = Utils.GetAbsolutePath(stageFileName)
+					if err != nil {
+						logger.Fatalf("Error getting absolute path for stage file: %v\n", err)
+					}
 
-			// Get file size for the new file
-			newFileSize, err := Utils.GetFileSize(stageFileName)
-			if err != nil {
-				logger.Fatalf("[!] Error getting file size: %v\n", err)
-				continue
-			}
+					fmt.Printf("[+] Stage %d saved to: %s\n", iterationCount, Colors.BoldCyan(stageFileName))
+				}
 
-			if iterationCount == 1 {
-				// For first stage, show entropy and current reduction percentage
-				fmt.Printf("\n[+] Stage %d Reduction - Overall PE Entropy: %s\n",
-					iterationCount,
-					Colors.CalculateColor2Entropy(currentEntropy))
-				fmt.Printf("[+] Stage %d Current Reduction Percentage: %s%%\n",
-					iterationCount,
-					Colors.BoldMagenta(fmt.Sprintf("%.2f", stageReductionPercentage)))
-				fmt.Printf("[+] Stage %d File Size: %s KB\n",
-					iterationCount,
-					Colors.BoldYellow(newFileSize))
-			} else {
-				// For subsequent stages, show all messages in desired order
-				fmt.Printf("\n[+] Stage %d Reduction - Overall PE Entropy: %s\n",
-					iterationCount,
-					Colors.CalculateColor2Entropy(currentEntropy))
-				fmt.Printf("[+] Stage %d Current Reduction Percentage: %s%%\n",
-					iterationCount,
-					Colors.BoldMagenta(fmt.Sprintf("%.2f", stageReductionPercentage)))
-				fmt.Printf("[+] Stage %d File Size: %s KB\n",
-					iterationCount,
-					Colors.BoldYellow(newFileSize))
-				fmt.Printf("[+] Stage %d Total Reduction Percentage: %s%%\n",
-					iterationCount,
-					Colors.BoldBlue(fmt.Sprintf("%.2f", totalReductionPercentage)))
-			}
+				// Check if we're stuck (entropy isn't decreasing significantly)
+				if lastEntropy-currentEntropy < 0.0001 {
+					stuckCount++
+					if stuckCount >= 3 { // If stuck for 3 iterations, break
+						fmt.Printf("\n[!] Entropy reduction plateaued after %d stages\n", iterationCount)
+						break
+					}
+				} else {
+					stuckCount = 0
+				}
 
-			// Get absolute path for stage file
-			stageFileName, err = Utils.GetAbsolutePath(stageFileName)
-			if err != nil {
-				logger.Fatalf("[!] Error getting absolute path for stage file: %v\n", err)
-				continue
+				lastEntropy = currentEntropy
 			}
 
-			fmt.Printf("[+] Stage %d saved to: %s\n", iterationCount, Colors.BoldCyan(stageFileName))
+			// Write the last stage as the final artifact, unless it was kept under the same name
+			if iterationCount > 0 && (!options.keepStages || options.output != "") {
+				outputFileName := options.finalPath(fileName, fileExtension, currentEntropy)
+				if err := Utils.WritePaddedFile(outputFileName, filePath, bytes.NewReader(padding)); err != nil {
+					logger.Fatalf("Error writing output file: %v\n", err)
+				}
 
-			// Check if we're stuck (entropy isn't decreasing significantly)
-			if lastEntropy-currentEntropy < 0.0001 {
-				stuckCount++
-				if stuckCount >= 3 { // If stuck for 3 iterations, break
-					fmt.Printf("\n[!] Entropy reduction plateaued after %d stages\n", iterationCount)
-					break
+				// Get absolute path for output file
+				outputFileName, err = Utils.GetAbsolutePath(outputFileName)
+				if err != nil {
+					logger.Fatalf("Error getting absolute path for output file: %v\n", err)
 				}
-			} else {
-				stuckCount = 0
-			}
 
-			lastEntropy = currentEntropy
+				fmt.Printf("\n[+] Output saved to: %s\n", Colors.BoldCyan(outputFileName))
+			}
 		}
 
 		// If graph flag is enabled
@@ -246,7 +405,7 @@ var freeArgument = &cobra.Command{
 					getDateTime = time.Now().Format("20060102-150405")
 
 					// Save the plot to a PNG file
-					outputFile := fmt.Sprintf("%s_Entropy_Reduction_%s.png", fileName, getDateTime)
+					outputFile := options.artifactPath(fmt.Sprintf("%s_Entropy_Reduction_%s.png", fileName, getDateTime))
 					if err := p.Save(8*vg.Inch, 6*vg.Inch, outputFile); err != nil {
 						logger.Fatalf("\n[!] Error saving plot: %v\n", err)
 					} else {
@@ -267,3 +426,276 @@ var freeArgument = &cobra.Command{
 		return nil
 	},
 }
+
+// paddingStream generates padding in chunks as it is read, topping it up
+// until the real entropy of the histogram reaches the target
+type paddingStream struct {
+	strategy     Reduce.Strategy
+	rng          *rand.Rand
+	histogram    *Calculate.Histogram
+	distribution [256]float64
+	target       float64
+	remaining    uint64 // Padding bytes left to generate
+	topUps       int
+	chunk        []byte // Generated padding not read yet
+}
+
+// Read function
+// Read generates the next chunk of padding whenever the previous one is used up.
+func (s *paddingStream) Read(p []byte) (int, error) {
+	for len(s.chunk) == 0 {
+		if s.remaining == 0 {
+			// Randomly generated padding may deviate from the expected
+			// distribution, so top it up a few times if the target is missed
+			if s.topUps >= maxPaddingTopUps {
+				return 0, io.EOF
+			}
+			extraSize, err := Calculate.SolvePaddingSize(s.histogram, s.distribution, s.target)
+			if err != nil {
+				return 0, err
+			}
+			if extraSize == 0 {
+				return 0, io.EOF
+			}
+			s.topUps++
+			s.remaining = extraSize
+		}
+
+		// Call function named Generate
+		size := min(s.remaining, paddingChunkSize)
+		s.chunk = s.strategy.Generate(int(size), s.rng)
+		s.histogram.Add(s.chunk)
+		s.remaining -= size
+	}
+
+	n := copy(p, s.chunk)
+	s.chunk = s.chunk[n:]
+
+	return n, nil
+}
+
+// exactReduction function
+// exactReduction solves the padding size needed to reach the target entropy
+// and writes a single output file of exactly that size.
+func exactReduction(filePath string, format string, histogram *Calculate.Histogram, initialEntropy float64, target float64, strategy Reduce.Strategy, rng *rand.Rand, fileName string, fileExtension string, options freeOptions) []StageData {
+	logger := log.New(os.Stderr, "[!] ", 0)
+
+	// Call function named Distribution
+	distribution := strategy.Distribution()
+
+	// Call function named SolvePaddingSize
+	paddingSize, err := Calculate.SolvePaddingSize(histogram, distribution, target)
+	if err != nil {
+		logger.Fatal("Error: ", err)
+	}
+
+	if paddingSize == 0 {
+		fmt.Printf("\n[+] Overall %s Entropy is already at or below target: %s\n", format, Colors.BoldGreen(fmt.Sprintf("%.5f", target)))
+		return nil
+	}
+
+	fmt.Printf("\n[+] Solved Padding Size: %s bytes\n", Colors.BoldYellow(paddingSize))
+
+	// Refuse sizes that would fill the disk, targets close to the entropy of
+	// the strategy itself need enormous padding
+	if paddingSize > maxPaddingSize {
+		logger.Fatalf("Error: Solved padding of %d bytes exceeds the limit of %d bytes, raise the target or use a strategy of lower entropy\n\n", paddingSize, uint64(maxPaddingSize))
+	}
+
+	// Stream the padding into a temporary file, its final name depends on
+	// the entropy that is only known once all padding is generated
+	stream := &paddingStream{
+		strategy:     strategy,
+		rng:          rng,
+		histogram:    histogram,
+		distribution: distribution,
+		target:       target,
+		remaining:    paddingSize,
+	}
+	tempFileName, err := Utils.CreatePaddedFile(filepath.Dir(options.finalPath(fileName, fileExtension, target)), filePath, stream)
+	if err != nil {
+		logger.Fatalf("Error writing output file: %v\n", err)
+	}
+
+	// Calculate final entropy
+	finalEntropy := histogram.Entropy()
+	totalReductionPercentage := ((initialEntropy - finalEntropy) / initialEntropy) * 100
+
+	// Build new filename for the output
+	outputFileName := options.finalPath(fileName, fileExtension, finalEntropy)
+	if err := os.Rename(tempFileName, outputFileName); err != nil {
+		os.Remove(tempFileName)
+		logger.Fatalf("Error writing output file: %v\n", err)
+	}
+
+	// Get file size for the new file
+	newFileSize, err := Utils.GetFileSize(outputFileName)
+	if err != nil {
+		logger.Fatalf("Error getting file size: %v\n", err)
+	}
+
+	// Get absolute path for output file
+	outputFileName, err = Utils.GetAbsolutePath(outputFileName)
+	if err != nil {
+		logger.Fatalf("Error getting absolute path for output file: %v\n", err)
+	}
+
+	fmt.Printf("[+] Exact Reduction - Overall %s Entropy: %s\n", format, Colors.CalculateColor2Entropy(finalEntropy))
+	fmt.Printf("[+] Total Reduction Percentage: %s%%\n", Colors.BoldBlue(fmt.Sprintf("%.2f", totalReductionPercentage)))
+	fmt.Printf("[+] Final File Size: %s KB\n", Colors.BoldYellow(newFileSize))
+	fmt.Printf("[+] Output saved to: %s\n", Colors.BoldCyan(outputFileName))
+
+	return []StageData{{1, finalEntropy}}
+}
+
+// dryRunReduction function
+// dryRunReduction predicts the stage entropies, final size and growth of a
+// reduction from the expected byte distribution of the strategy, without
+// generating padding or writing any file.
+func dryRunReduction(format string, histogram *Calculate.Histogram, fileSize int64, target float64, strategy Reduce.Strategy, exact bool) {
+	logger := log.New(os.Stderr, "[!] ", 0)
+
+	fmt.Printf("\n[*] Dry Run: %s\n", Colors.BoldWhite("no files will be written"))
+
+	// Call function named Distribution
+	distribution := strategy.Distribution()
+
+	initialEntropy := histogram.Entropy()
+	predictedEntropy := initialEntropy
+	var paddingSize uint64
+
+	// If exact flag is enabled
+	if exact {
+		// Call function named SolvePaddingSize
+		var err error
+		paddingSize, err = Calculate.SolvePaddingSize(histogram, distribution, target)
+		if err != nil {
+			logger.Fatal("Error: ", err)
+		}
+		predictedEntropy = Calculate.PredictEntropy(histogram, distribution, paddingSize)
+
+		fmt.Printf("\n[+] Solved Padding Size: %s bytes\n", Colors.BoldYellow(paddingSize))
+		if paddingSize > maxPaddingSize {
+			logger.Printf("Warning: A real run refuses padding above %d bytes\n", uint64(maxPaddingSize))
+		}
+	} else {
+		// Follow the staged loop of a real run, including its plateau detection
+		lastEntropy := predictedEntropy
+		stuckCount := 0
+		for stage := 1; predictedEntropy > target && stage <= Reduce.MaxStages; stage++ {
+			paddingSize += Reduce.StageSize
+			predictedEntropy = Calculate.PredictEntropy(histogram, distribution, paddingSize)
+
+			fmt.Printf("\n[+] Stage %d Predicted Overall %s Entropy: %s\n", stage, format, Colors.CalculateColor2Entropy(predictedEntropy))
+			fmt.Printf("[+] Stage %d Predicted File Size: %s KB\n", stage, Colors.BoldYellow(float64(fileSize+int64(paddingSize))/1024.0))
+
+			if lastEntropy-predictedEntropy < 0.0001 {
+				stuckCount++
+				if stuckCount >= 3 {
+					fmt.Printf("\n[!] Entropy reduction would plateau after %d stages\n", stage)
+					break
+				}
+			} else {
+				stuckCount = 0
+			}
+			lastEntropy = predictedEntropy
+		}
+	}
+
+	// Summarize the cost of the reduction
+	finalSize := fileSize + int64(paddingSize)
+	fmt.Printf("\n[+] Predicted Final Overall %s Entropy: %s\n", format, Colors.CalculateColor2Entropy(predictedEntropy))
+	fmt.Printf("[+] Predicted Total Reduction Percentage: %s%%\n", Colors.BoldBlue(fmt.Sprintf("%.2f", (initialEntropy-predictedEntropy)/initialEntropy*100)))
+	fmt.Printf("[+] Predicted Final File Size: %s KB\n", Colors.BoldYellow(float64(finalSize)/1024.0))
+	fmt.Printf("[+] Predicted Growth: %s%%\n", Colors.BoldMagenta(fmt.Sprintf("%.2f", float64(paddingSize)/float64(max(fileSize, 1))*100)))
+}
+
+// printStrategies function
+// printStrategies lists every registered strategy with its description.
+func printStrategies() {
+	fmt.Printf("[*] Available Strategies:\n\n")
+	for _, strategy := range Reduce.Strategies() {
+		fmt.Printf("[+] %s: %s\n", Colors.BoldBlue(strategy.Name()), strategy.Description())
+	}
+	fmt.Println()
+}
+
+// registerPattern function
+// registerPattern registers the pattern strategy with the fill given as a hex
+// string or read from a file.
+func registerPattern(pattern string, patternFile string) error {
+	var fill []byte
+	var err error
+
+	if patternFile != "" {
+		fill, err = os.ReadFile(patternFile)
+		if err != nil {
+			return fmt.Errorf("failed to read pattern file: %w", err)
+		}
+	} else {
+		// Call function named ParsePattern
+		fill, err = Reduce.ParsePattern(pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Call function named NewPatternStrategy
+	strategy, err := Reduce.NewPatternStrategy(fill)
+	if err != nil {
+		return err
+	}
+	Reduce.RegisterStrategy(strategy)
+
+	return nil
+}
+
+// registerWordList function
+// registerWordList registers the word strategy with the words of a word list
+// file, or the embedded dictionary when none is set, and a separator.
+func registerWordList(wordlist string, separatorName string) error {
+	words := WordList.DefaultWords()
+	if wordlist != "" {
+		var err error
+		words, err = WordList.LoadWords(wordlist)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Call function named Separator
+	separator, err := WordList.Separator(separatorName)
+	if err != nil {
+		return err
+	}
+
+	// Call function named NewWordStrategy
+	strategy, err := Reduce.NewWordStrategy(words, separator)
+	if err != nil {
+		return err
+	}
+	Reduce.RegisterStrategy(strategy)
+
+	return nil
+}
+
+// printPaddingDistribution function
+// printPaddingDistribution prints the entropy and the most frequent bytes of
+// the padding the strategy generates.
+func printPaddingDistribution(distribution [256]float64) {
+	// Order the byte values by expected share
+	values := make([]int, 0, 256)
+	for value, share := range distribution {
+		if share > 0 {
+			values = append(values, value)
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return distribution[values[i]] > distribution[values[j]] })
+
+	fmt.Printf("[+] Padding Entropy: %s\n", Colors.CalculateColor2Entropy(Calculate.DistributionEntropy(distribution)))
+	fmt.Printf("[+] Padding Byte Distribution (Top %s of %s Distinct Values):\n", Colors.BoldYellow(min(byteHistogramTop, len(values))), Colors.BoldYellow(len(values)))
+	for _, value := range values[:min(byteHistogramTop, len(values))] {
+		share := distribution[value] * 100
+		fmt.Printf("	>>> 0x%02x %6.2f%% %s\n", value, share, Colors.BoldBlue(strings.Repeat("#", int(share/2)+1)))
+	}
+}
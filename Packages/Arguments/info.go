@@ -3,22 +3,65 @@ package Arguments
 import (
 	"SugarFree/Packages/Calculate"
 	"SugarFree/Packages/Colors"
+	"SugarFree/Packages/Graph"
 	"SugarFree/Packages/Output"
 	"SugarFree/Packages/Utils"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
+	"gonum.org/v1/plot/vg"
 )
 
+// byteHistogramTop is the number of byte values printed in byte histograms
+const byteHistogramTop = 16
+
+// Exit codes returned when an entropy gate trips
+const (
+	exitEntropyAbove        = 2
+	exitSectionEntropyAbove = 3
+	exitBothEntropyAbove    = 4
+	exitAnalysisFailed      = 5 // An input could not be measured, so no gate can pass
+)
+
+// infoOptions holds the analysis settings shared by every input file
+type infoOptions struct {
+	withProfile    bool
+	excludeOverlay bool
+	raw            bool
+	stats          bool
+	metrics        bool
+	window         int64
+	step           int64
+	startTime      time.Time
+}
+
+// fileAnalysis holds the results of analyzing a single input file
+type fileAnalysis struct {
+	report   Output.Report
+	profile  []Calculate.WindowEntropy
+	regions  []Calculate.Region
+	warnings []string // Optional parts of the file that could not be analyzed
+	err      error
+}
+
 // infoArgument represents the 'info' command in the CLI.
 var infoArgument = &cobra.Command{
 	// Use defines how the command should be called.
-	Use:          "info",
+	Use:          "info [files...]",
 	Short:        "Info command",
-	Long:         "Calculates the entropy of a PE file and its sections",
+	Long:         "Calculates the entropy of PE, ELF and Mach-O files and their sections",
 	SilenceUsage: true,
 	Aliases:      []string{"INFO", "Info"},
 
@@ -26,8 +69,37 @@ var infoArgument = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := log.New(os.Stderr, "[!] ", 0)
 
-		// Call function named ShowAscii
-		ShowAscii()
+		// Get variables from the command line
+		files, _ := cmd.Flags().GetStringSlice("file")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		workers, _ := cmd.Flags().GetInt("workers")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		detail, _ := cmd.Flags().GetBool("detail")
+		output, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		profile, _ := cmd.Flags().GetBool("profile")
+		window, _ := cmd.Flags().GetInt64("window")
+		step, _ := cmd.Flags().GetInt64("step")
+		profileOutput, _ := cmd.Flags().GetString("profile-output")
+		graph, _ := cmd.Flags().GetBool("graph")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		failAbove, _ := cmd.Flags().GetFloat64("fail-above")
+		failSectionAbove, _ := cmd.Flags().GetFloat64("fail-section-above")
+		excludeOverlay, _ := cmd.Flags().GetBool("exclude-overlay")
+		raw, _ := cmd.Flags().GetBool("raw")
+		stats, _ := cmd.Flags().GetBool("stats")
+		metrics, _ := cmd.Flags().GetBool("metrics")
+
+		// Machine-readable reports without an output file go to stdout,
+		// so the human-readable console output is discarded
+		format = strings.ToLower(format)
+		console := io.Writer(os.Stdout)
+		if format != "text" && output == "" {
+			console = io.Discard
+		} else {
+			// Call function named ShowAscii
+			ShowAscii()
+		}
 
 		// Check if additional arguments were provided.
 		if len(os.Args) <= 2 {
@@ -42,81 +114,163 @@ var infoArgument = &cobra.Command{
 			os.Exit(0)
 		}
 
-		// Define variables
-		var sectionEntropy string
-
-		// Get variables from the command line
-		file, _ := cmd.Flags().GetString("file")
-		output, _ := cmd.Flags().GetString("output")
+		// Positional arguments are treated like additional file flags
+		files = append(files, args...)
 
 		// Check if the file flag is empty
-		if file == "" {
+		if len(files) == 0 {
 			logger.Fatal("Error: Input file is missing. Please provide it to continue...\n\n")
 		}
 
+		// Call function named NewReportWriter
+		reportWriter, err := Output.NewReportWriter(format)
+		if err != nil {
+			logger.Fatal("Error: ", err)
+		}
+
+		// Call function named ExpandInputs
+		filePaths, err := Utils.ExpandInputs(files, recursive)
+		if err != nil {
+			// A missing input is an artifact that could not be measured
+			logger.Println("Error:", err)
+			os.Exit(exitAnalysisFailed)
+		}
+		if len(filePaths) == 0 {
+			logger.Println("Error: No input files found. Please provide valid files to continue...")
+			os.Exit(exitAnalysisFailed)
+		}
+
+		// A single profile export cannot hold several files
+		if profileOutput != "" && len(filePaths) > 1 {
+			logger.Fatal("Error: Profile output supports a single input file only...\n\n")
+		}
+
+		// Default the step to the window size
+		if step == 0 {
+			step = window
+		}
+		if window <= 0 || step <= 0 {
+			logger.Fatalf("Error: Window and step must be positive, got %d and %d...\n\n", window, step)
+		}
+
 		// Record the start time
 		calculateStartTime := time.Now()
 
 		// Get the current date and time
 		getDateTime := time.Now().Format("2006-01-02 15:04:05")
 
-		fmt.Printf("[*] Starting PE analysis on %s\n\n", Colors.BoldWhite(getDateTime))
+		fmt.Fprintf(console, "[*] Starting binary analysis on %s\n\n", Colors.BoldWhite(getDateTime))
 
-		// Call function named GetAbsolutePath
-		filePath, err := Utils.GetAbsolutePath(file)
-		if err != nil {
-			logger.Fatal("Error: ", err)
+		// Call function named analyzeFiles
+		options := infoOptions{
+			withProfile:    profile || profileOutput != "" || graph || format == "html",
+			excludeOverlay: excludeOverlay,
+			raw:            raw,
+			stats:          stats,
+			metrics:        metrics,
+			window:         window,
+			step:           step,
+			startTime:      calculateStartTime,
 		}
+		analyses := analyzeFiles(filePaths, options, workers)
 
-		// Call function named GetFileSize
-		fileSize, err := Utils.GetFileSize(filePath)
-		if err != nil {
-			logger.Fatal("Error: ", err)
+		// Report failed files and keep the rest
+		var succeeded []fileAnalysis
+		failed := 0
+		for i, analysis := range analyses {
+			for _, warning := range analysis.warnings {
+				logger.Printf("Warning: %s: %s\n", filePaths[i], warning)
+			}
+			if analysis.err != nil {
+				logger.Printf("Error: %s: %v\n", filePaths[i], analysis.err)
+				failed++
+				continue
+			}
+			succeeded = append(succeeded, analysis)
+		}
+		if len(succeeded) == 0 {
+			logger.Println("Error: No input file could be analyzed...")
+			os.Exit(exitAnalysisFailed)
 		}
 
-		// Call function named ReadSections
-		sections, err := Calculate.ReadSections(filePath)
-		if err != nil {
-			log.Fatal(err)
+		// Call function named sortAnalyses
+		if err := sortAnalyses(succeeded, sortBy); err != nil {
+			logger.Fatal("Error: ", err)
 		}
 
-		// Convert Calcualte.SectionEntropy to Output.Section
-		var outputSections []Output.Section
-		for _, section := range sections {
-			outputSections = append(outputSections, Output.Section{
-				Name:    section.Name,
-				Entropy: section.Entropy,
-			})
+		// A single file keeps the detailed layout, batches start with a summary
+		if len(filePaths) == 1 {
+			printAnalysis(console, succeeded[0], profile, window, step)
+		} else {
+			printSummary(console, succeeded)
+			if detail {
+				for _, analysis := range succeeded {
+					fmt.Fprintln(console)
+					printAnalysis(console, analysis, profile, window, step)
+				}
+			}
 		}
 
-		// Read the file
-		buf, err := os.ReadFile(file)
-		if err != nil {
-			logger.Fatal("Error: ", err)
+		// Check if the profile output flag is empty.
+		if profileOutput != "" {
+			// Call function named WriteProfileCSV
+			if err := Output.WriteProfileCSV(succeeded[0].report.Profile, profileOutput); err != nil {
+				logger.Fatal("Error: ", err)
+			}
+
+			// Call function named GetAbsolutePath
+			profileFilePath, err := Utils.GetAbsolutePath(profileOutput)
+			if err != nil {
+				logger.Fatal("Error: ", err)
+			}
+
+			fmt.Fprintf(console, "\n[+] Entropy profile saved to: %s\n", Colors.BoldCyan(profileFilePath))
 		}
 
-		fullEntropy := Calculate.CalculateFullEntropy(buf)
+		// If graph flag is enabled
+		if graph {
+			for _, analysis := range succeeded {
+				// Call function named EntropyProfile
+				p, err := Graph.EntropyProfile("Entropy Profile - "+filepath.Base(analysis.report.File), analysis.profile, analysis.regions, threshold)
+				if err != nil {
+					logger.Fatal("Error: ", err)
+				}
 
-		// Print the results
-		fmt.Printf("[+] Analyzing PE File: %s\n", Colors.BoldCyan(file))
-		fmt.Printf("[+] File Size: %s KB\n", Colors.BoldYellow(fileSize))
-		fmt.Printf("[+] Overall PE Entropy: %s\n\n", Colors.CalculateColor2Entropy(fullEntropy))
-		fmt.Print("[+] PE Sections Entropy:\n")
-		for _, section := range sections {
-			// Call function ColorManager
-			sectionName := Colors.ColorNameManager(section.Name)
+				// Save the plot to a PNG file, batches tag every chart with a short
+				// hash of the input path so equal base names do not collide
+				fileName, _ := Utils.SplitFileName(filepath.Base(analysis.report.File))
+				if len(succeeded) > 1 {
+					pathHash := sha256.Sum256([]byte(analysis.report.File))
+					fileName = fmt.Sprintf("%s_%s", fileName, hex.EncodeToString(pathHash[:4]))
+				}
+				outputFile := fmt.Sprintf("%s_Entropy_Profile_%s.png", fileName, time.Now().Format("20060102-150405"))
+				if err := p.Save(12*vg.Inch, 6*vg.Inch, outputFile); err != nil {
+					logger.Fatal("Error saving plot: ", err)
+				}
 
-			// Call function named CalculateColor2Entropy
-			sectionEntropy = Colors.CalculateColor2Entropy(section.Entropy)
+				fmt.Fprintf(console, "\n[+] Entropy profile graph saved to: %s\n", Colors.BoldYellow(outputFile))
+			}
+		}
 
-			// Print the results
-			fmt.Printf("	>>> \"%s\" Scored Entropy Of Value: %s\n", sectionName, sectionEntropy)
+		// Collect the reports in summary order
+		var reports []Output.Report
+		for _, analysis := range succeeded {
+			reports = append(reports, analysis.report)
+		}
+
+		// Write machine-readable reports to stdout when no output file is set
+		if format != "text" && output == "" {
+			if err := reportWriter.Write(os.Stdout, reports); err != nil {
+				logger.Fatal("Error: ", err)
+			}
 		}
 
 		// Check if the output flag is empty.
 		if output != "" {
-			// Call function named WriteToFile
-			Output.Write2File(outputSections, output, file, fileSize, fullEntropy, getDateTime)
+			// Call function named WriteReportFile
+			if err := Output.WriteReportFile(reportWriter, reports, output); err != nil {
+				logger.Fatal("Error: ", err)
+			}
 
 			// Call function named GetAbsolutePath
 			outputFilePath, err := Utils.GetAbsolutePath(output)
@@ -124,7 +278,7 @@ var infoArgument = &cobra.Command{
 				logger.Fatal("Error: ", err)
 			}
 
-			fmt.Printf("\n[+] Results saved to: %s\n", Colors.BoldCyan(outputFilePath))
+			fmt.Fprintf(console, "\n[+] Results saved to: %s\n", Colors.BoldCyan(outputFilePath))
 		}
 
 		// Record the end time
@@ -134,8 +288,604 @@ var infoArgument = &cobra.Command{
 		calculateDurationTime := calculateEndTime.Sub(calculateStartTime)
 
 		// Print the duration
-		fmt.Printf("\n[*] Completed in: %s\n\n", Colors.BoldWhite(calculateDurationTime))
+		fmt.Fprintf(console, "\n[*] Completed in: %s\n\n", Colors.BoldWhite(calculateDurationTime))
+
+		// Call function named checkEntropyGates
+		exitCode := checkEntropyGates(succeeded,
+			cmd.Flags().Changed("fail-above"), failAbove,
+			cmd.Flags().Changed("fail-section-above"), failSectionAbove)
+
+		// Files that could not be analyzed fail the run even if every gate passed
+		if failed > 0 {
+			logger.Printf("Error: %d of %d input files could not be analyzed\n", failed, len(filePaths))
+			os.Exit(exitAnalysisFailed)
+		}
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
 
 		return nil
 	},
 }
+
+// checkEntropyGates function
+// checkEntropyGates reports every file and section above the enabled limits
+// on stderr and returns the exit code matching the gates that tripped.
+func checkEntropyGates(analyses []fileAnalysis, checkOverall bool, overallLimit float64, checkSection bool, sectionLimit float64) int {
+	logger := log.New(os.Stderr, "[!] ", 0)
+	overallTripped := false
+	sectionTripped := false
+
+	for _, analysis := range analyses {
+		report := analysis.report
+
+		if checkOverall && report.Entropy > overallLimit {
+			overallTripped = true
+			logger.Printf("Gate: %s overall entropy %.5f is above %.5f\n", report.File, report.Entropy, overallLimit)
+		}
+
+		// Only real sections are gated, pseudo-regions such as gaps and
+		// manifest resources are reported but never trip the gate
+		if checkSection {
+			for _, section := range report.SectionsOfKind(Calculate.KindSection) {
+				if section.Entropy > sectionLimit {
+					sectionTripped = true
+					logger.Printf("Gate: %s %s \"%s\" entropy %.5f is above %.5f\n", report.File, section.Kind, section.Name, section.Entropy, sectionLimit)
+				}
+			}
+		}
+	}
+
+	switch {
+	case overallTripped && sectionTripped:
+		return exitBothEntropyAbove
+	case overallTripped:
+		return exitEntropyAbove
+	case sectionTripped:
+		return exitSectionEntropyAbove
+	default:
+		return 0
+	}
+}
+
+// analyzeFiles function
+// analyzeFiles analyzes every file on a bounded pool of workers and returns
+// the results in input order.
+func analyzeFiles(filePaths []string, options infoOptions, workers int) []fileAnalysis {
+	analyses := make([]fileAnalysis, len(filePaths))
+	workers = max(1, min(workers, len(filePaths)))
+
+	// Feed file indexes to the workers
+	jobs := make(chan int)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range jobs {
+				// Call function named analyzeFile
+				analyses[index] = analyzeFile(filePaths[index], options)
+			}
+		}()
+	}
+
+	for index := range filePaths {
+		jobs <- index
+	}
+	close(jobs)
+	waitGroup.Wait()
+
+	return analyses
+}
+
+// analyzeFile function
+// analyzeFile computes the report of a single file.
+func analyzeFile(filePath string, options infoOptions) fileAnalysis {
+	// Open the file
+	inputFile, err := os.Open(filePath)
+	if err != nil {
+		return fileAnalysis{err: err}
+	}
+	defer inputFile.Close()
+
+	// Get file information
+	fileInfo, err := inputFile.Stat()
+	if err != nil {
+		return fileAnalysis{err: err}
+	}
+
+	// Call function named OpenBinary, data of an unknown format falls back
+	// to a raw blob
+	var binary Calculate.Binary
+	if options.raw {
+		binary = Calculate.OpenRawBinary(inputFile, fileInfo.Size())
+	} else {
+		binary, err = Calculate.OpenBinary(inputFile, fileInfo.Size())
+		if err != nil {
+			return fileAnalysis{err: fmt.Errorf("%w (use --raw to analyze it as a blob)", err)}
+		}
+	}
+
+	// Call function named AnalyzeRegions
+	sections, err := Calculate.AnalyzeRegions(inputFile, binary.Regions())
+	if err != nil {
+		return fileAnalysis{err: err}
+	}
+
+	// Headers and gaps cover everything outside the sections and overlay
+	pseudoSections, err := Calculate.AnalyzeRegions(inputFile, Calculate.PseudoRegions(binary))
+	if err != nil {
+		return fileAnalysis{err: err}
+	}
+
+	// Call function named ReadCLRRegions, broken metadata is common in
+	// obfuscated assemblies and only drops the .NET regions
+	var warnings []string
+	clrRegions, err := Calculate.ReadCLRRegions(binary)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("skipping .NET metadata: %v", err))
+		clrRegions = nil
+	}
+
+	// Metadata streams and manifest resources of .NET assemblies
+	clrSections, err := Calculate.AnalyzeRegions(inputFile, clrRegions)
+	if err != nil {
+		return fileAnalysis{err: err}
+	}
+	pseudoSections = append(pseudoSections, clrSections...)
+
+	// Call function named newOutputSections
+	outputSections := newOutputSections(sections)
+	outputPseudoRegions := newOutputSections(pseudoSections)
+
+	// Call function named ReadResources, a damaged resource directory only
+	// drops the resources
+	resources, err := Calculate.ReadResources(binary)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("skipping resources: %v", err))
+		resources = nil
+	}
+
+	// Convert Calculate.Resource to Output.Resource
+	var outputResources []Output.Resource
+	for _, resource := range resources {
+		outputResources = append(outputResources, Output.Resource{
+			Type:     resource.Type,
+			Name:     resource.Name,
+			Language: resource.Language,
+			Offset:   resource.Offset,
+			Size:     resource.Size,
+			Entropy:  resource.Entropy,
+			Magic:    resource.Magic,
+		})
+	}
+
+	// Hash the file and gather its statistics and metrics while streaming it through the histogram
+	md5Hash, sha1Hash, sha256Hash := md5.New(), sha1.New(), sha256.New()
+	statisticsWriter := &Calculate.StatisticsWriter{}
+	var metricsWriter *Calculate.MetricsWriter
+	writers := []io.Writer{md5Hash, sha1Hash, sha256Hash}
+	if options.stats {
+		writers = append(writers, statisticsWriter)
+	}
+	if options.metrics {
+		metricsWriter = Calculate.NewMetricsWriter()
+		writers = append(writers, metricsWriter)
+	}
+	histogram, err := Calculate.HistogramFromReader(io.TeeReader(io.NewSectionReader(inputFile, 0, fileInfo.Size()), io.MultiWriter(writers...)))
+	if err != nil {
+		return fileAnalysis{err: err}
+	}
+
+	// Report the data appended after the last section separately
+	var outputOverlay *Output.Section
+	overallHistogram := *histogram
+	if overlay, ok := binary.Overlay(); ok {
+		// Call function named HistogramFromReader
+		overlayHistogram, err := Calculate.HistogramFromReader(io.NewSectionReader(inputFile, overlay.Offset, overlay.Size))
+		if err != nil {
+			return fileAnalysis{err: err}
+		}
+
+		outputOverlay = &Output.Section{
+			Name:    overlay.Name,
+			Kind:    overlay.Kind,
+			Offset:  overlay.Offset,
+			RawSize: overlay.Size,
+			Entropy: overlayHistogram.Entropy(),
+		}
+
+		// Leave the overlay out of the overall figure on request
+		if options.excludeOverlay {
+			overallHistogram.Subtract(overlayHistogram)
+		}
+	}
+
+	// Compute the distribution statistics and metrics of the file and every region
+	var outputStatistics *Output.Statistics
+	var outputMetrics *Output.Metrics
+	if options.stats {
+		outputStatistics = Output.NewStatistics(statisticsWriter.Statistics())
+	}
+	if options.metrics {
+		outputMetrics = Output.NewMetrics(metricsWriter.Metrics())
+	}
+	if options.stats || options.metrics {
+		for i := range outputSections {
+			// Call function named addDistribution
+			if err := addDistribution(inputFile, &outputSections[i], options); err != nil {
+				return fileAnalysis{err: err}
+			}
+		}
+		for i := range outputPseudoRegions {
+			if err := addDistribution(inputFile, &outputPseudoRegions[i], options); err != nil {
+				return fileAnalysis{err: err}
+			}
+		}
+
+		if outputOverlay != nil {
+			if err := addDistribution(inputFile, outputOverlay, options); err != nil {
+				return fileAnalysis{err: err}
+			}
+		}
+	}
+
+	// Build the report
+	analysis := fileAnalysis{
+		report: Output.Report{
+			GeneratedAt: options.startTime.Format(time.RFC3339),
+			File:        filePath,
+			Format:      binary.Format(),
+			Hashes: Output.Hashes{
+				MD5:    hex.EncodeToString(md5Hash.Sum(nil)),
+				SHA1:   hex.EncodeToString(sha1Hash.Sum(nil)),
+				SHA256: hex.EncodeToString(sha256Hash.Sum(nil)),
+			},
+			Size:            int64(histogram.Total()),
+			Entropy:         overallHistogram.Entropy(),
+			OverlayExcluded: options.excludeOverlay,
+			Sections:        outputSections,
+			PseudoRegions:   outputPseudoRegions,
+			Overlay:         outputOverlay,
+			Resources:       outputResources,
+			Statistics:      outputStatistics,
+			Metrics:         outputMetrics,
+			Histogram:       *histogram,
+		}, warnings: warnings,
+	}
+
+	// Check if the profile is needed, raw blobs always get one
+	if options.withProfile || binary.Format() == Calculate.FormatRaw {
+		// Call function named buildProfile
+		analysis.profile, analysis.regions, err = buildProfile(binary, options.window, options.step)
+		if err != nil {
+			return fileAnalysis{err: err}
+		}
+
+		// Convert Calculate.WindowEntropy to Output.Window
+		for _, window := range analysis.profile {
+			analysis.report.Profile = append(analysis.report.Profile, Output.Window{
+				Offset:  window.Offset,
+				Size:    window.Size,
+				Entropy: window.Entropy,
+				Region:  window.Region,
+			})
+		}
+
+		// Convert Calculate.Region to Output.Region
+		for _, region := range analysis.regions {
+			analysis.report.Regions = append(analysis.report.Regions, Output.Region{
+				Name:   region.Name,
+				Kind:   region.Kind,
+				Offset: region.Offset,
+				Size:   region.Size,
+			})
+		}
+	}
+
+	return analysis
+}
+
+// newOutputSections function
+// newOutputSections converts Calculate.SectionEntropy to Output.Section.
+func newOutputSections(sections []Calculate.SectionEntropy) []Output.Section {
+	var outputSections []Output.Section
+	for _, section := range sections {
+		outputSections = append(outputSections, Output.Section{
+			Name:            section.Name,
+			Kind:            section.Kind,
+			Offset:          section.Offset,
+			RawSize:         section.Size,
+			VirtualSize:     section.VirtualSize,
+			Characteristics: section.Characteristics,
+			Entropy:         section.Entropy,
+		})
+	}
+
+	return outputSections
+}
+
+// addDistribution function
+// addDistribution computes the distribution statistics and metrics of a
+// section as enabled by the options.
+func addDistribution(file io.ReaderAt, section *Output.Section, options infoOptions) error {
+	if options.stats {
+		// Call function named StatisticsAt
+		statistics, err := Calculate.StatisticsAt(file, section.Offset, section.RawSize)
+		if err != nil {
+			return fmt.Errorf("failed to read %s %s: %w", section.Kind, section.Name, err)
+		}
+		section.Statistics = Output.NewStatistics(statistics)
+	}
+
+	if options.metrics {
+		// Call function named MetricsAt
+		metrics, err := Calculate.MetricsAt(file, section.Offset, section.RawSize)
+		if err != nil {
+			return fmt.Errorf("failed to read %s %s: %w", section.Kind, section.Name, err)
+		}
+		section.Metrics = Output.NewMetrics(metrics)
+	}
+
+	return nil
+}
+
+// buildProfile function
+// buildProfile computes the windowed entropy profile of a file and labels
+// every window with the regions it falls into.
+func buildProfile(binary Calculate.Binary, window int64, step int64) ([]Calculate.WindowEntropy, []Calculate.Region, error) {
+	// Call function named EntropyProfile
+	profile, err := Calculate.EntropyProfile(binary.ReaderAt(), binary.Size(), window, step)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Call function named LayoutRegions
+	regions := Calculate.LayoutRegions(binary)
+
+	// Call function named AnnotateProfile
+	Calculate.AnnotateProfile(profile, regions)
+
+	return profile, regions, nil
+}
+
+// sortAnalyses function
+// sortAnalyses orders the analyses by name ascending, or by size, overall
+// entropy or maximum section entropy descending.
+func sortAnalyses(analyses []fileAnalysis, sortBy string) error {
+	var less func(a, b Output.Report) bool
+
+	switch strings.ToLower(sortBy) {
+	case "name":
+		less = func(a, b Output.Report) bool { return a.File < b.File }
+	case "size":
+		less = func(a, b Output.Report) bool { return a.Size > b.Size }
+	case "entropy":
+		less = func(a, b Output.Report) bool { return a.Entropy > b.Entropy }
+	case "max-section":
+		less = func(a, b Output.Report) bool {
+			maxA, _ := a.MaxSection()
+			maxB, _ := b.MaxSection()
+			return maxA.Entropy > maxB.Entropy
+		}
+	default:
+		return fmt.Errorf("invalid sort key %q, valid keys are: name, size, entropy, max-section", sortBy)
+	}
+
+	sort.SliceStable(analyses, func(i, j int) bool {
+		return less(analyses[i].report, analyses[j].report)
+	})
+
+	return nil
+}
+
+// printSummary function
+// printSummary prints one table row per analyzed file.
+func printSummary(console io.Writer, analyses []fileAnalysis) {
+	fmt.Fprintf(console, "[+] Analyzed Files: %s\n\n", Colors.BoldYellow(len(analyses)))
+	fmt.Fprintf(console, "	%-12s %-9s %-24s %s\n", "Size (KB)", "Entropy", "Max Section", "File")
+	for _, analysis := range analyses {
+		report := analysis.report
+
+		// Show the highest scoring section next to its value
+		maxSectionText := fmt.Sprintf("%-24s", "-")
+		if maxSection, ok := report.MaxSection(); ok {
+			maxSectionText = fmt.Sprintf("%s %s", Colors.CalculateColor2Entropy(maxSection.Entropy), Colors.ColorNameManager(maxSection.Name))
+			maxSectionText += strings.Repeat(" ", max(0, 16-len(maxSection.Name)))
+		}
+
+		fmt.Fprintf(console, "	%-12.2f %s   %s %s\n",
+			float64(report.Size)/1024.0,
+			Colors.CalculateColor2Entropy(report.Entropy),
+			maxSectionText,
+			Colors.BoldCyan(report.File))
+	}
+}
+
+// printAnalysis function
+// printAnalysis prints the detailed results of a single file.
+func printAnalysis(console io.Writer, analysis fileAnalysis, profile bool, window int64, step int64) {
+	report := analysis.report
+
+	// Print the results
+	fmt.Fprintf(console, "[+] Analyzing %s File: %s\n", report.Format, Colors.BoldCyan(report.File))
+	fmt.Fprintf(console, "[+] File Size: %s KB\n", Colors.BoldYellow(float64(report.Size)/1024.0))
+	if report.OverlayExcluded && report.Overlay != nil {
+		fmt.Fprintf(console, "[+] Overall %s Entropy (Excluding Overlay): %s\n", report.Format, Colors.CalculateColor2Entropy(report.Entropy))
+	} else {
+		fmt.Fprintf(console, "[+] Overall %s Entropy: %s\n", report.Format, Colors.CalculateColor2Entropy(report.Entropy))
+	}
+
+	// Raw blobs have no layout, their byte histogram explains the entropy instead
+	if report.Format == Calculate.FormatRaw {
+		// Call function named printByteHistogram
+		printByteHistogram(console, report.Histogram)
+	} else {
+		// Print the overlay appended after the last section
+		if report.Overlay != nil {
+			fmt.Fprintf(console, "[+] Overlay Offset: %s\n", Colors.BoldYellow(fmt.Sprintf("0x%08x", report.Overlay.Offset)))
+			fmt.Fprintf(console, "[+] Overlay Size: %s KB (%s%% of file)\n", Colors.BoldYellow(float64(report.Overlay.RawSize)/1024.0), Colors.BoldMagenta(fmt.Sprintf("%.2f", report.OverlayShare())))
+			fmt.Fprintf(console, "[+] Overlay Entropy: %s\n", Colors.CalculateColor2Entropy(report.Overlay.Entropy))
+		} else {
+			fmt.Fprintf(console, "[+] Overlay: %s\n", Colors.BoldGreen("None"))
+		}
+		fmt.Fprintln(console)
+		fmt.Fprintf(console, "[+] %s Sections Entropy:\n", report.Format)
+		for _, section := range report.SectionsOfKind(Calculate.KindSection) {
+			// Call function ColorManager
+			sectionName := Colors.ColorNameManager(section.Name)
+
+			// Call function named CalculateColor2Entropy
+			sectionEntropy := Colors.CalculateColor2Entropy(section.Entropy)
+
+			// Print the results
+			fmt.Fprintf(console, "	>>> \"%s\" Scored Entropy Of Value: %s\n", sectionName, sectionEntropy)
+		}
+
+		// Print the segments and slices of formats that have them
+		for _, kind := range report.OtherKinds() {
+			fmt.Fprintf(console, "\n[+] %s %s Entropy:\n", report.Format, Output.KindTitle(kind))
+			for _, section := range report.SectionsOfKind(kind) {
+				// Only segments and slices are sized like sections, show the size of the rest
+				sectionSize := ""
+				if kind != Calculate.KindSegment && kind != Calculate.KindSlice {
+					sectionSize = fmt.Sprintf(" (%s bytes)", Colors.BoldYellow(section.RawSize))
+				}
+
+				fmt.Fprintf(console, "	>>> \"%s\" Scored Entropy Of Value: %s%s\n", Colors.BoldWhite(section.Name), Colors.CalculateColor2Entropy(section.Entropy), sectionSize)
+			}
+		}
+
+		// Print the resources to pinpoint what drives the resource section entropy
+		if len(report.Resources) > 0 {
+			fmt.Fprintf(console, "\n[+] %s Resources Entropy:\n", report.Format)
+			for _, resource := range report.Resources {
+				// Flag resources that start with an executable or archive magic
+				embedded := ""
+				if resource.Magic != "" {
+					embedded = " " + Colors.BoldRed("[embedded "+resource.Magic+"]")
+				}
+
+				fmt.Fprintf(console, "	>>> \"%s\" Scored Entropy Of Value: %s (0x%08x, %s bytes)%s\n", Colors.BoldBlue(resource.Path()), Colors.CalculateColor2Entropy(resource.Entropy), resource.Offset, Colors.BoldYellow(resource.Size), embedded)
+			}
+		}
+
+		// Sections, headers, gaps and overlay add up to the file size
+		fmt.Fprintf(console, "\n[+] Region Coverage: %s of %s bytes\n", Colors.BoldYellow(report.Coverage()), Colors.BoldYellow(report.Size))
+	}
+
+	// Print the distribution statistics of the file and every region
+	if report.Statistics != nil {
+		// Call function named printStatistics
+		printStatistics(console, report)
+	}
+
+	// Print the alternative randomness metrics of the file and every region
+	if report.Metrics != nil {
+		// Call function named printMetrics
+		printMetrics(console, report)
+	}
+
+	// Check if the profile flag is enabled, raw blobs always show their profile
+	if profile || report.Format == Calculate.FormatRaw {
+		fmt.Fprintf(console, "\n[+] Entropy Profile (Window: %s bytes, Step: %s bytes):\n", Colors.BoldYellow(window), Colors.BoldYellow(step))
+		for _, window := range analysis.profile {
+			fmt.Fprintf(console, "	>>> 0x%08x %10d %s %s\n", window.Offset, window.Size, Colors.CalculateColor2Entropy(window.Entropy), Colors.ColorNameManager(window.Region))
+		}
+	}
+}
+
+// printByteHistogram function
+// printByteHistogram prints the most frequent byte values of a file.
+func printByteHistogram(console io.Writer, histogram [256]uint64) {
+	// Order the byte values by frequency
+	total := uint64(0)
+	values := make([]int, 0, 256)
+	for value, count := range histogram {
+		total += count
+		if count > 0 {
+			values = append(values, value)
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return histogram[values[i]] > histogram[values[j]] })
+
+	fmt.Fprintf(console, "\n[+] Byte Histogram (Top %s of %s Distinct Values):\n", Colors.BoldYellow(min(byteHistogramTop, len(values))), Colors.BoldYellow(len(values)))
+	for _, value := range values[:min(byteHistogramTop, len(values))] {
+		share := float64(histogram[value]) / float64(total) * 100
+		fmt.Fprintf(console, "	>>> 0x%02x %10d %6.2f%% %s\n", value, histogram[value], share, Colors.BoldBlue(strings.Repeat("#", int(share/2)+1)))
+	}
+}
+
+// printStatistics function
+// printStatistics prints the byte histogram and the distribution statistics
+// of a file, followed by the statistics of every region.
+func printStatistics(console io.Writer, report Output.Report) {
+	statistics := report.Statistics
+
+	// Raw blobs already show their byte histogram
+	if report.Format != Calculate.FormatRaw {
+		// Call function named printByteHistogram
+		printByteHistogram(console, statistics.Histogram)
+	}
+
+	fmt.Fprintf(console, "\n[+] Byte Distribution Statistics:\n")
+	fmt.Fprintf(console, "	>>> Chi-Square: %s (%s for uniform data)\n", Colors.BoldYellow(fmt.Sprintf("%.2f", statistics.ChiSquare)), Colors.BoldWhite("~255"))
+	fmt.Fprintf(console, "	>>> Arithmetic Mean: %s (%s for random data)\n", Colors.BoldYellow(fmt.Sprintf("%.5f", statistics.Mean)), Colors.BoldWhite("127.5"))
+	fmt.Fprintf(console, "	>>> Serial Correlation: %s (%s for uncorrelated data)\n", Colors.BoldYellow(fmt.Sprintf("%.6f", statistics.SerialCorrelation)), Colors.BoldWhite("0.0"))
+	fmt.Fprintf(console, "	>>> Monte Carlo Pi: %s (error %s%%)\n", Colors.BoldYellow(fmt.Sprintf("%.9f", statistics.MonteCarloPi)), Colors.BoldMagenta(fmt.Sprintf("%.2f", statistics.MonteCarloPiError)))
+
+	// Regions are listed in report order, the overlay last
+	sections := report.SectionsWithOverlay()
+	if len(sections) == 0 {
+		return
+	}
+
+	fmt.Fprintf(console, "\n[+] %s Region Statistics (Chi-Square, Mean, Serial Correlation, Pi Error):\n", report.Format)
+	for _, section := range sections {
+		if section.Statistics == nil {
+			continue
+		}
+
+		fmt.Fprintf(console, "	>>> %-10s %-24s %14.2f %10.5f %10.6f %8.2f%%\n",
+			section.Kind,
+			Colors.BoldWhite(section.Name),
+			section.Statistics.ChiSquare,
+			section.Statistics.Mean,
+			section.Statistics.SerialCorrelation,
+			section.Statistics.MonteCarloPiError)
+	}
+}
+
+// printMetrics function
+// printMetrics prints the conditional, Rényi and min-entropy and the
+// compression ratio of a file, followed by the metrics of every region.
+func printMetrics(console io.Writer, report Output.Report) {
+	metrics := report.Metrics
+
+	fmt.Fprintf(console, "\n[+] Alternative Randomness Metrics:\n")
+	fmt.Fprintf(console, "	>>> Conditional Entropy (Order-1): %s\n", Colors.CalculateColor2Entropy(metrics.ConditionalEntropy))
+	fmt.Fprintf(console, "	>>> Rényi Entropy (Order-2): %s\n", Colors.CalculateColor2Entropy(metrics.RenyiEntropy))
+	fmt.Fprintf(console, "	>>> Min-Entropy: %s\n", Colors.CalculateColor2Entropy(metrics.MinEntropy))
+	fmt.Fprintf(console, "	>>> Compression Ratio (DEFLATE): %s\n", Colors.BoldYellow(fmt.Sprintf("%.4f", metrics.CompressionRatio)))
+
+	// Regions are listed in report order, the overlay last
+	sections := report.SectionsWithOverlay()
+	if len(sections) == 0 {
+		return
+	}
+
+	fmt.Fprintf(console, "\n[+] %s Region Metrics (Conditional, Rényi, Min-Entropy, Compression Ratio):\n", report.Format)
+	for _, section := range sections {
+		if section.Metrics == nil {
+			continue
+		}
+
+		fmt.Fprintf(console, "	>>> %-10s %-24s %8.5f %8.5f %8.5f %8.4f\n",
+			section.Kind,
+			Colors.BoldWhite(section.Name),
+			section.Metrics.ConditionalEntropy,
+			section.Metrics.RenyiEntropy,
+			section.Metrics.MinEntropy,
+			section.Metrics.CompressionRatio)
+	}
+}
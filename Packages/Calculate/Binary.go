@@ -0,0 +1,166 @@
+package Calculate
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Binary interface
+// Binary describes the file layout of a parsed executable independently of
+// its format, so every command can work on regions generically.
+type Binary interface {
+	Format() string          // Detected format name
+	Size() int64             // File size in bytes
+	Regions() []Region       // Sections, segments and slices
+	Headers() []Region       // Header regions preceding the content
+	Overlay() (Region, bool) // Data appended after the last region
+	ReaderAt() io.ReaderAt   // Underlying file data
+}
+
+// binaryLayout holds the layout shared by every Binary implementation
+type binaryLayout struct {
+	format  string
+	size    int64
+	regions []Region
+	headers []Region
+	overlay Region
+	reader  io.ReaderAt
+}
+
+// Format function
+func (b *binaryLayout) Format() string {
+	return b.format
+}
+
+// Size function
+func (b *binaryLayout) Size() int64 {
+	return b.size
+}
+
+// Regions function
+func (b *binaryLayout) Regions() []Region {
+	return b.regions
+}
+
+// Headers function
+func (b *binaryLayout) Headers() []Region {
+	return b.headers
+}
+
+// Overlay function
+func (b *binaryLayout) Overlay() (Region, bool) {
+	return b.overlay, b.overlay.Size > 0
+}
+
+// ReaderAt function
+func (b *binaryLayout) ReaderAt() io.ReaderAt {
+	return b.reader
+}
+
+// addRegion function
+// addRegion appends a region clamped to the file size, skipping regions that
+// start past the end of the file or hold no file data.
+func (b *binaryLayout) addRegion(region Region) {
+	if region.Size <= 0 || region.Offset < 0 || region.Offset >= b.size {
+		return
+	}
+	region.Size = min(region.Size, b.size-region.Offset)
+	b.regions = append(b.regions, region)
+}
+
+// setOverlay function
+// setOverlay marks everything from contentEnd to the end of the file as overlay.
+func (b *binaryLayout) setOverlay(contentEnd int64) {
+	if contentEnd > 0 && contentEnd < b.size {
+		b.overlay = Region{Name: "<overlay>", Kind: KindOverlay, Offset: contentEnd, Size: b.size - contentEnd}
+	}
+}
+
+// rawBinary struct
+// rawBinary treats data of an unknown format as a single blob.
+type rawBinary struct {
+	binaryLayout
+}
+
+// OpenRawBinary function
+// OpenRawBinary treats r as a raw blob regardless of its format.
+func OpenRawBinary(r io.ReaderAt, size int64) Binary {
+	return &rawBinary{binaryLayout{format: FormatRaw, size: size, reader: r}}
+}
+
+// OpenBinary function
+// OpenBinary detects the format of r and parses its layout. Data of an
+// unknown format is returned as a raw blob without regions.
+func OpenBinary(r io.ReaderAt, size int64) (Binary, error) {
+	switch format := DetectFormat(r); format {
+	case FormatPE:
+		return newPEBinary(r, size)
+	case FormatELF:
+		return newELFBinary(r, size)
+	case FormatMachO, FormatMachOUniversal:
+		return newMachOBinary(r, size, format)
+	case FormatUnknown:
+		return OpenRawBinary(r, size), nil
+	default:
+		return nil, fmt.Errorf("unsupported file format %s", format)
+	}
+}
+
+// LayoutRegions function
+// LayoutRegions returns the headers, sections and overlay of a binary sorted
+// by offset, with every byte no region accounts for reported as a gap, so
+// the region sizes add up to the file size.
+func LayoutRegions(binary Binary) []Region {
+	regions := append([]Region{}, binary.Headers()...)
+	for _, region := range binary.Regions() {
+		if region.Kind == KindSection {
+			regions = append(regions, region)
+		}
+	}
+	if overlay, ok := binary.Overlay(); ok {
+		regions = append(regions, overlay)
+	}
+
+	// Raw blobs have no layout to fill
+	if len(regions) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool { return regions[i].Offset < regions[j].Offset })
+
+	// Fill the slack between regions with gaps
+	var layout []Region
+	cursor := int64(0)
+	for _, region := range regions {
+		if region.Offset > cursor {
+			layout = append(layout, gapRegion(cursor, region.Offset))
+		}
+		layout = append(layout, region)
+		cursor = max(cursor, region.Offset+region.Size)
+	}
+	if cursor < binary.Size() {
+		layout = append(layout, gapRegion(cursor, binary.Size()))
+	}
+
+	return layout
+}
+
+// PseudoRegions function
+// PseudoRegions returns the header and gap regions of a binary, which hold
+// everything outside its sections and overlay.
+func PseudoRegions(binary Binary) []Region {
+	var regions []Region
+	for _, region := range LayoutRegions(binary) {
+		if region.Kind == KindHeader || region.Kind == KindGap {
+			regions = append(regions, region)
+		}
+	}
+
+	return regions
+}
+
+// gapRegion function
+func gapRegion(start int64, end int64) Region {
+	return Region{Name: fmt.Sprintf("<gap@0x%x>", start), Kind: KindGap, Offset: start, Size: end - start}
+}
@@ -0,0 +1,416 @@
+package Calculate
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// Kinds of regions found inside the CLR metadata of .NET assemblies
+const (
+	KindStream           = "stream"
+	KindManifestResource = "manifest resource"
+)
+
+// clrMetadataSignature is the "BSJB" signature of the metadata root
+const clrMetadataSignature = 0x424a5342
+
+// manifestResourceTable is the index of the ManifestResource metadata table
+const manifestResourceTable = 0x28
+
+// Column kinds of the metadata table schema
+const (
+	colU16    = -1 // Two byte constant
+	colU32    = -2 // Four byte constant
+	colString = -3 // Index into #Strings
+	colGUID   = -4 // Index into #GUID
+	colBlob   = -5 // Index into #Blob
+)
+
+// codedIndex describes a coded index by its tag size and target tables
+type codedIndex struct {
+	tagBits int
+	tables  []int
+}
+
+// Coded indices used by the tables preceding ManifestResource
+var (
+	typeDefOrRef        = &codedIndex{2, []int{0x02, 0x01, 0x1b}}
+	hasConstant         = &codedIndex{2, []int{0x04, 0x08, 0x17}}
+	hasCustomAttribute  = &codedIndex{5, []int{0x06, 0x04, 0x01, 0x02, 0x08, 0x09, 0x0a, 0x00, 0x0e, 0x17, 0x14, 0x11, 0x1a, 0x1b, 0x20, 0x23, 0x26, 0x27, 0x28, 0x2a, 0x2c, 0x2b}}
+	hasFieldMarshal     = &codedIndex{1, []int{0x04, 0x08}}
+	hasDeclSecurity     = &codedIndex{2, []int{0x02, 0x06, 0x20}}
+	memberRefParent     = &codedIndex{3, []int{0x02, 0x01, 0x1a, 0x06, 0x1b}}
+	hasSemantics        = &codedIndex{1, []int{0x14, 0x17}}
+	methodDefOrRef      = &codedIndex{1, []int{0x06, 0x0a}}
+	memberForwarded     = &codedIndex{1, []int{0x04, 0x06}}
+	implementation      = &codedIndex{2, []int{0x26, 0x23, 0x27}}
+	customAttributeType = &codedIndex{3, []int{0x06, 0x0a}}
+	resolutionScope     = &codedIndex{2, []int{0x00, 0x1a, 0x23, 0x01}}
+)
+
+// metadataSchema lists the columns of every table up to ManifestResource,
+// a column is a constant, a heap index, a table index or a coded index
+var metadataSchema = [manifestResourceTable + 1][]any{
+	0x00: {colU16, colString, colGUID, colGUID, colGUID},                                   // Module
+	0x01: {resolutionScope, colString, colString},                                          // TypeRef
+	0x02: {colU32, colString, colString, typeDefOrRef, 0x04, 0x06},                         // TypeDef
+	0x03: {0x04},                                                                           // FieldPtr
+	0x04: {colU16, colString, colBlob},                                                     // Field
+	0x05: {0x06},                                                                           // MethodPtr
+	0x06: {colU32, colU16, colU16, colString, colBlob, 0x08},                               // MethodDef
+	0x07: {0x08},                                                                           // ParamPtr
+	0x08: {colU16, colU16, colString},                                                      // Param
+	0x09: {0x02, typeDefOrRef},                                                             // InterfaceImpl
+	0x0a: {memberRefParent, colString, colBlob},                                            // MemberRef
+	0x0b: {colU16, hasConstant, colBlob},                                                   // Constant
+	0x0c: {hasCustomAttribute, customAttributeType, colBlob},                               // CustomAttribute
+	0x0d: {hasFieldMarshal, colBlob},                                                       // FieldMarshal
+	0x0e: {colU16, hasDeclSecurity, colBlob},                                               // DeclSecurity
+	0x0f: {colU16, colU32, 0x02},                                                           // ClassLayout
+	0x10: {colU32, 0x04},                                                                   // FieldLayout
+	0x11: {colBlob},                                                                        // StandAloneSig
+	0x12: {0x02, 0x14},                                                                     // EventMap
+	0x13: {0x14},                                                                           // EventPtr
+	0x14: {colU16, colString, typeDefOrRef},                                                // Event
+	0x15: {0x02, 0x17},                                                                     // PropertyMap
+	0x16: {0x17},                                                                           // PropertyPtr
+	0x17: {colU16, colString, colBlob},                                                     // Property
+	0x18: {colU16, 0x06, hasSemantics},                                                     // MethodSemantics
+	0x19: {0x02, methodDefOrRef, methodDefOrRef},                                           // MethodImpl
+	0x1a: {colString},                                                                      // ModuleRef
+	0x1b: {colBlob},                                                                        // TypeSpec
+	0x1c: {colU16, memberForwarded, colString, 0x1a},                                       // ImplMap
+	0x1d: {colU32, 0x04},                                                                   // FieldRVA
+	0x1e: {colU32, colU32},                                                                 // EncLog
+	0x1f: {colU32},                                                                         // EncMap
+	0x20: {colU32, colU16, colU16, colU16, colU16, colU32, colBlob, colString, colString},  // Assembly
+	0x21: {colU32},                                                                         // AssemblyProcessor
+	0x22: {colU32, colU32, colU32},                                                         // AssemblyOS
+	0x23: {colU16, colU16, colU16, colU16, colU32, colBlob, colString, colString, colBlob}, // AssemblyRef
+	0x24: {colU32, 0x23},                                                                   // AssemblyRefProcessor
+	0x25: {colU32, colU32, colU32, 0x23},                                                   // AssemblyRefOS
+	0x26: {colU32, colString, colBlob},                                                     // File
+	0x27: {colU32, colU32, colString, colString, implementation},                           // ExportedType
+	0x28: {colU32, colU32, colString, implementation},                                      // ManifestResource
+}
+
+// clrStream describes one metadata stream
+type clrStream struct {
+	name   string
+	offset int64 // File offset of the stream
+	size   int64
+}
+
+// metadataTables holds the layout of the #~ stream needed to read its rows
+type metadataTables struct {
+	heapSizes byte
+	rows      [64]uint32
+	offset    int64 // File offset of the first table
+}
+
+// ReadCLRRegions function
+// ReadCLRRegions locates the CLR header of a .NET assembly and returns every
+// metadata stream and every manifest resource embedded in the file. Binaries
+// without a CLR header have no such regions.
+func ReadCLRRegions(file Binary) ([]Region, error) {
+	peFile, ok := file.(*peBinary)
+	if !ok {
+		return nil, nil
+	}
+
+	// Locate the CLR header
+	clrDirectory := peFile.dataDirectory(pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR)
+	if clrDirectory.VirtualAddress == 0 || clrDirectory.Size == 0 {
+		return nil, nil
+	}
+	clrOffset, ok := peFile.rvaToOffset(clrDirectory.VirtualAddress)
+	if !ok {
+		return nil, fmt.Errorf("CLR header RVA 0x%x is outside the file", clrDirectory.VirtualAddress)
+	}
+
+	// IMAGE_COR20_HEADER
+	header := make([]byte, 32)
+	if _, err := peFile.reader.ReadAt(header, clrOffset); err != nil {
+		return nil, fmt.Errorf("failed to read CLR header: %w", err)
+	}
+	metadataRVA := binary.LittleEndian.Uint32(header[8:])
+	resourcesRVA := binary.LittleEndian.Uint32(header[24:])
+	resourcesSize := int64(binary.LittleEndian.Uint32(header[28:]))
+
+	metadataOffset, ok := peFile.rvaToOffset(metadataRVA)
+	if !ok {
+		return nil, fmt.Errorf("CLR metadata RVA 0x%x is outside the file", metadataRVA)
+	}
+
+	// Call function named readCLRStreams
+	streams, err := readCLRStreams(peFile.reader, metadataOffset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CLR metadata: %w", err)
+	}
+
+	var regions []Region
+	var tablesStream, stringsStream *clrStream
+	for i, stream := range streams {
+		regions = append(regions, Region{Name: stream.name, Kind: KindStream, Offset: stream.offset, Size: stream.size})
+
+		switch stream.name {
+		case "#~", "#-":
+			tablesStream = &streams[i]
+		case "#Strings":
+			stringsStream = &streams[i]
+		}
+	}
+
+	// Manifest resources live in the resources blob named by the tables
+	if tablesStream == nil || resourcesRVA == 0 || resourcesSize == 0 {
+		return clampRegions(regions, file.Size()), nil
+	}
+	resourcesOffset, ok := peFile.rvaToOffset(resourcesRVA)
+	if !ok {
+		return clampRegions(regions, file.Size()), nil
+	}
+
+	// Call function named readManifestResources
+	resources, err := readManifestResources(peFile.reader, *tablesStream, stringsStream, resourcesOffset, resourcesSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest resources: %w", err)
+	}
+	regions = append(regions, resources...)
+
+	return clampRegions(regions, file.Size()), nil
+}
+
+// readCLRStreams function
+// readCLRStreams parses the metadata root at offset and returns its streams.
+func readCLRStreams(r io.ReaderAt, offset int64) ([]clrStream, error) {
+	// Signature, version, reserved and the version string length
+	root := make([]byte, 16)
+	if _, err := r.ReadAt(root, offset); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(root) != clrMetadataSignature {
+		return nil, fmt.Errorf("invalid metadata signature")
+	}
+	versionLength := int64(binary.LittleEndian.Uint32(root[12:]))
+
+	// Flags and the number of streams follow the version string
+	counts := make([]byte, 4)
+	position := offset + 16 + versionLength
+	if _, err := r.ReadAt(counts, position); err != nil {
+		return nil, err
+	}
+	streamCount := int(binary.LittleEndian.Uint16(counts[2:]))
+	position += 4
+
+	// Stream headers hold an offset, a size and a padded name
+	var streams []clrStream
+	for i := 0; i < streamCount; i++ {
+		header := make([]byte, 8+32)
+		n, err := r.ReadAt(header, position)
+		if n < 9 {
+			return nil, fmt.Errorf("truncated stream header: %w", err)
+		}
+		header = header[:n]
+
+		end := bytes.IndexByte(header[8:], 0)
+		if end < 0 {
+			return nil, fmt.Errorf("stream name is not terminated")
+		}
+
+		streams = append(streams, clrStream{
+			name:   string(header[8 : 8+end]),
+			offset: offset + int64(binary.LittleEndian.Uint32(header)),
+			size:   int64(binary.LittleEndian.Uint32(header[4:])),
+		})
+		position += 8 + int64((end+4)&^3)
+	}
+
+	return streams, nil
+}
+
+// readManifestResources function
+// readManifestResources reads the ManifestResource table and returns the
+// resources embedded in this file, each stored as a length-prefixed blob.
+func readManifestResources(r io.ReaderAt, tablesStream clrStream, stringsStream *clrStream, resourcesOffset int64, resourcesSize int64) ([]Region, error) {
+	// Call function named readMetadataTables
+	tables, err := readMetadataTables(r, tablesStream)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip every table stored before ManifestResource
+	position := tables.offset
+	for table := 0; table < manifestResourceTable; table++ {
+		position += int64(tables.rowSize(table)) * int64(tables.rows[table])
+	}
+
+	var regions []Region
+	rowSize := tables.rowSize(manifestResourceTable)
+	row := make([]byte, rowSize)
+	for i := uint32(0); i < tables.rows[manifestResourceTable]; i++ {
+		if _, err := r.ReadAt(row, position+int64(i)*int64(rowSize)); err != nil {
+			return nil, err
+		}
+
+		// Offset, Flags, Name and Implementation
+		dataOffset := int64(binary.LittleEndian.Uint32(row))
+		nameIndex, next := tables.readColumn(row, 8, colString)
+		implementationIndex, _ := tables.readColumn(row, next, implementation)
+
+		// Resources stored in other files or assemblies have no data here
+		if implementationIndex != 0 || dataOffset+4 > resourcesSize {
+			continue
+		}
+
+		length := make([]byte, 4)
+		if _, err := r.ReadAt(length, resourcesOffset+dataOffset); err != nil {
+			return nil, err
+		}
+
+		regions = append(regions, Region{
+			Name:   readHeapString(r, stringsStream, nameIndex),
+			Kind:   KindManifestResource,
+			Offset: resourcesOffset + dataOffset + 4,
+			Size:   min(int64(binary.LittleEndian.Uint32(length)), resourcesSize-dataOffset-4),
+		})
+	}
+
+	return regions, nil
+}
+
+// readMetadataTables function
+// readMetadataTables parses the header of the #~ stream.
+func readMetadataTables(r io.ReaderAt, stream clrStream) (*metadataTables, error) {
+	header := make([]byte, 24)
+	if _, err := r.ReadAt(header, stream.offset); err != nil {
+		return nil, err
+	}
+
+	tables := &metadataTables{heapSizes: header[6]}
+	valid := binary.LittleEndian.Uint64(header[8:])
+
+	// One row count follows for every present table
+	count := make([]byte, 4)
+	position := stream.offset + 24
+	for table := 0; table < 64; table++ {
+		if valid&(1<<table) == 0 {
+			continue
+		}
+		if _, err := r.ReadAt(count, position); err != nil {
+			return nil, err
+		}
+		tables.rows[table] = binary.LittleEndian.Uint32(count)
+		position += 4
+	}
+
+	// Bit 0x40 of the heap sizes marks an extra data field before the tables
+	if tables.heapSizes&0x40 != 0 {
+		position += 4
+	}
+	tables.offset = position
+
+	return tables, nil
+}
+
+// rowSize function
+// rowSize returns the size in bytes of one row of a table.
+func (t *metadataTables) rowSize(table int) int {
+	size := 0
+	for _, column := range metadataSchema[table] {
+		size += t.columnSize(column)
+	}
+
+	return size
+}
+
+// columnSize function
+// columnSize returns the size in bytes of one column, which depends on the
+// heap sizes and the row counts of the referenced tables.
+func (t *metadataTables) columnSize(column any) int {
+	switch column := column.(type) {
+	case *codedIndex:
+		maxRows := uint32(0)
+		for _, table := range column.tables {
+			maxRows = max(maxRows, t.rows[table])
+		}
+		if bits.Len32(maxRows) <= 16-column.tagBits {
+			return 2
+		}
+		return 4
+	case int:
+		switch column {
+		case colU16:
+			return 2
+		case colU32:
+			return 4
+		case colString:
+			return t.heapIndexSize(0x01)
+		case colGUID:
+			return t.heapIndexSize(0x02)
+		case colBlob:
+			return t.heapIndexSize(0x04)
+		default:
+			if t.rows[column] < 1<<16 {
+				return 2
+			}
+			return 4
+		}
+	default:
+		return 0
+	}
+}
+
+// heapIndexSize function
+func (t *metadataTables) heapIndexSize(flag byte) int {
+	if t.heapSizes&flag != 0 {
+		return 4
+	}
+
+	return 2
+}
+
+// readColumn function
+// readColumn reads a column value at position and returns it with the
+// position of the next column.
+func (t *metadataTables) readColumn(row []byte, position int, column any) (uint32, int) {
+	if t.columnSize(column) == 4 {
+		return binary.LittleEndian.Uint32(row[position:]), position + 4
+	}
+
+	return uint32(binary.LittleEndian.Uint16(row[position:])), position + 2
+}
+
+// readHeapString function
+// readHeapString reads a null-terminated string from the #Strings heap.
+func readHeapString(r io.ReaderAt, stream *clrStream, index uint32) string {
+	if stream == nil || int64(index) >= stream.size {
+		return fmt.Sprintf("#%d", index)
+	}
+
+	name := make([]byte, min(stream.size-int64(index), 1024))
+	n, _ := r.ReadAt(name, stream.offset+int64(index))
+	if end := bytes.IndexByte(name[:n], 0); end >= 0 {
+		n = end
+	}
+
+	return string(name[:n])
+}
+
+// clampRegions function
+// clampRegions drops regions outside the file and clamps the rest to its size.
+func clampRegions(regions []Region, size int64) []Region {
+	var clamped []Region
+	for _, region := range regions {
+		if region.Size <= 0 || region.Offset < 0 || region.Offset >= size {
+			continue
+		}
+		region.Size = min(region.Size, size-region.Offset)
+		clamped = append(clamped, region)
+	}
+
+	return clamped
+}
@@ -0,0 +1,278 @@
+package Calculate
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"testing"
+)
+
+// Layout of the synthetic assembly
+const (
+	testSectionRVA    = 0x2000
+	testSectionOffset = 0x200
+	testMetadataStart = 0x100 // Metadata root offset inside the section
+)
+
+// testResource is a manifest resource embedded in the synthetic assembly
+type testResource struct {
+	name string
+	data []byte
+}
+
+// testAssembly describes the metadata tables of a synthetic assembly. Row
+// sizes are spelled out per table from ECMA-335 II.22 rather than derived
+// from metadataSchema, so a wrong column shifts the manifest resources.
+type testAssembly struct {
+	heapSizes byte
+	rows      map[int]int // Row counts of the tables before ManifestResource
+	rowSizes  map[int]int // Expected row size of every table in rows
+	resources []testResource
+}
+
+// pad4 function
+func pad4(data []byte) []byte {
+	return append(data, make([]byte, (4-len(data)%4)%4)...)
+}
+
+// build function
+// build returns the assembly file and the regions ReadCLRRegions must find.
+func (a testAssembly) build(t *testing.T) ([]byte, []Region) {
+	t.Helper()
+	le := binary.LittleEndian
+
+	// #Strings heap with the resource names
+	strings := []byte{0}
+	nameIndexes := make([]uint32, len(a.resources))
+	for i, resource := range a.resources {
+		nameIndexes[i] = uint32(len(strings))
+		strings = append(append(strings, resource.name...), 0)
+	}
+	strings = pad4(strings)
+
+	// Resources blob of length-prefixed data
+	var blob []byte
+	dataOffsets := make([]uint32, len(a.resources))
+	for i, resource := range a.resources {
+		dataOffsets[i] = uint32(len(blob))
+		blob = le.AppendUint32(blob, uint32(len(resource.data)))
+		blob = pad4(append(blob, resource.data...))
+	}
+
+	// #~ header and the row counts of every present table
+	rows := map[int]int{manifestResourceTable: len(a.resources)}
+	for table, count := range a.rows {
+		rows[table] = count
+	}
+	var valid uint64
+	for table := range rows {
+		valid |= 1 << table
+	}
+	tables := []byte{0, 0, 0, 0, 2, 0, a.heapSizes, 1}
+	tables = le.AppendUint64(tables, valid)
+	tables = le.AppendUint64(tables, 0)
+	for table := 0; table < 64; table++ {
+		if count, ok := rows[table]; ok {
+			tables = le.AppendUint32(tables, uint32(count))
+		}
+	}
+	if a.heapSizes&0x40 != 0 {
+		tables = le.AppendUint32(tables, 0)
+	}
+
+	// Rows before ManifestResource are filled with bytes that turn any
+	// misaligned read into an offset outside the resources blob
+	for table := 0; table < manifestResourceTable; table++ {
+		tables = append(tables, bytes.Repeat([]byte{0xaa}, rows[table]*a.rowSizes[table])...)
+	}
+	for i := range a.resources {
+		tables = le.AppendUint32(tables, dataOffsets[i])
+		tables = le.AppendUint32(tables, 1)
+		if a.heapSizes&0x01 != 0 {
+			tables = le.AppendUint32(tables, nameIndexes[i])
+		} else {
+			tables = le.AppendUint16(tables, uint16(nameIndexes[i]))
+		}
+		tables = le.AppendUint16(tables, 0)
+	}
+	tables = pad4(tables)
+
+	// Metadata root with its stream headers
+	streams := []struct {
+		name string
+		data []byte
+	}{
+		{"#~", tables},
+		{"#Strings", strings},
+		{"#US", []byte{0, 0, 0, 0}},
+		{"#GUID", make([]byte, 16)},
+		{"#Blob", []byte{0, 0, 0, 0}},
+	}
+	version := []byte("v4.0.30319\x00\x00")
+	headerSize := 16 + len(version) + 4
+	for _, stream := range streams {
+		headerSize += 8 + len(pad4(append([]byte(stream.name), 0)))
+	}
+	metadata := le.AppendUint32(nil, clrMetadataSignature)
+	metadata = le.AppendUint16(metadata, 1)
+	metadata = le.AppendUint16(metadata, 1)
+	metadata = le.AppendUint32(metadata, 0)
+	metadata = le.AppendUint32(metadata, uint32(len(version)))
+	metadata = append(metadata, version...)
+	metadata = le.AppendUint16(metadata, 0)
+	metadata = le.AppendUint16(metadata, uint16(len(streams)))
+
+	var expected []Region
+	var body []byte
+	for _, stream := range streams {
+		offset := headerSize + len(body)
+		metadata = le.AppendUint32(metadata, uint32(offset))
+		metadata = le.AppendUint32(metadata, uint32(len(stream.data)))
+		metadata = append(metadata, pad4(append([]byte(stream.name), 0))...)
+		body = append(body, stream.data...)
+
+		expected = append(expected, Region{
+			Name:   stream.name,
+			Kind:   KindStream,
+			Offset: testSectionOffset + testMetadataStart + int64(offset),
+			Size:   int64(len(stream.data)),
+		})
+	}
+	metadata = append(metadata, body...)
+
+	// Section holding the CLR header, the metadata and the resources
+	resourcesStart := testMetadataStart + len(pad4(metadata))
+	section := make([]byte, resourcesStart+len(blob))
+	copy(section[testMetadataStart:], metadata)
+	copy(section[resourcesStart:], blob)
+	section = append(section, make([]byte, (0x200-len(section)%0x200)%0x200)...)
+
+	// IMAGE_COR20_HEADER
+	le.PutUint32(section[0:], 72)
+	le.PutUint16(section[4:], 2)
+	le.PutUint16(section[6:], 5)
+	le.PutUint32(section[8:], testSectionRVA+testMetadataStart)
+	le.PutUint32(section[12:], uint32(len(metadata)))
+	le.PutUint32(section[16:], 1)
+	le.PutUint32(section[24:], uint32(testSectionRVA+resourcesStart))
+	le.PutUint32(section[28:], uint32(len(blob)))
+
+	for i, resource := range a.resources {
+		expected = append(expected, Region{
+			Name:   resource.name,
+			Kind:   KindManifestResource,
+			Offset: testSectionOffset + int64(resourcesStart) + int64(dataOffsets[i]) + 4,
+			Size:   int64(len(resource.data)),
+		})
+	}
+
+	// PE32 headers with a single section
+	optionalHeader := pe.OptionalHeader32{
+		Magic:                 0x10b,
+		SizeOfCode:            uint32(len(section)),
+		BaseOfCode:            testSectionRVA,
+		ImageBase:             0x400000,
+		SectionAlignment:      0x2000,
+		FileAlignment:         0x200,
+		MajorSubsystemVersion: 4,
+		SizeOfImage:           uint32(testSectionRVA + len(section)),
+		SizeOfHeaders:         testSectionOffset,
+		Subsystem:             3,
+		NumberOfRvaAndSizes:   16,
+	}
+	optionalHeader.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR] = pe.DataDirectory{VirtualAddress: testSectionRVA, Size: 72}
+	sectionHeader := pe.SectionHeader32{
+		VirtualSize:      uint32(len(section)),
+		VirtualAddress:   testSectionRVA,
+		SizeOfRawData:    uint32(len(section)),
+		PointerToRawData: testSectionOffset,
+		Characteristics:  0x60000020,
+	}
+	copy(sectionHeader.Name[:], ".text")
+
+	var file bytes.Buffer
+	dos := make([]byte, 0x80)
+	copy(dos, "MZ")
+	le.PutUint32(dos[0x3c:], 0x80)
+	file.Write(dos)
+	file.WriteString("PE\x00\x00")
+	binary.Write(&file, le, pe.FileHeader{Machine: pe.IMAGE_FILE_MACHINE_I386, NumberOfSections: 1, SizeOfOptionalHeader: 0xe0, Characteristics: 0x2102})
+	binary.Write(&file, le, optionalHeader)
+	binary.Write(&file, le, sectionHeader)
+	file.Write(make([]byte, testSectionOffset-file.Len()))
+	file.Write(section)
+
+	return file.Bytes(), expected
+}
+
+// TestReadCLRRegions function
+// TestReadCLRRegions checks the stream and manifest resource regions of
+// synthetic assemblies with small and wide heap indices, the extra data
+// field and coded indices widened by large tables.
+func TestReadCLRRegions(t *testing.T) {
+	resources := []testResource{
+		{"Payload.bin", bytes.Repeat([]byte{0x5a}, 200)},
+		{"Notes.txt", []byte("hello world text")},
+	}
+
+	// Module, TypeRef, TypeDef, Field, MethodDef, Param, MemberRef,
+	// CustomAttribute, StandAloneSig, Assembly and AssemblyRef
+	rows := map[int]int{0x00: 1, 0x01: 3, 0x02: 2, 0x04: 1, 0x06: 3, 0x08: 1, 0x0a: 2, 0x0c: 3, 0x11: 1, 0x20: 1, 0x23: 1}
+
+	tests := []struct {
+		name     string
+		assembly testAssembly
+	}{
+		{
+			"small heaps",
+			testAssembly{
+				rows:      rows,
+				rowSizes:  map[int]int{0x00: 10, 0x01: 6, 0x02: 14, 0x04: 6, 0x06: 14, 0x08: 6, 0x0a: 6, 0x0c: 6, 0x11: 2, 0x20: 22, 0x23: 20},
+				resources: resources,
+			},
+		},
+		{
+			"wide heaps with extra data",
+			testAssembly{
+				heapSizes: 0x47,
+				rows:      rows,
+				rowSizes:  map[int]int{0x00: 18, 0x01: 10, 0x02: 18, 0x04: 10, 0x06: 18, 0x08: 8, 0x0a: 10, 0x0c: 8, 0x11: 4, 0x20: 28, 0x23: 28},
+				resources: resources,
+			},
+		},
+		{
+			// 16384 TypeRef rows widen ResolutionScope, TypeDefOrRef,
+			// MemberRefParent and HasCustomAttribute to four bytes
+			"wide coded indices",
+			testAssembly{
+				rows:      map[int]int{0x00: 1, 0x01: 1 << 14, 0x02: 2, 0x04: 1, 0x06: 3, 0x08: 1, 0x0a: 2, 0x0c: 3, 0x11: 1, 0x20: 1, 0x23: 1},
+				rowSizes:  map[int]int{0x00: 10, 0x01: 8, 0x02: 16, 0x04: 6, 0x06: 14, 0x08: 6, 0x0a: 8, 0x0c: 8, 0x11: 2, 0x20: 22, 0x23: 20},
+				resources: resources,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, expected := test.assembly.build(t)
+
+			file, err := OpenBinary(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			regions, err := ReadCLRRegions(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(regions) != len(expected) {
+				t.Fatalf("got %d regions %v, want %d %v", len(regions), regions, len(expected), expected)
+			}
+			for i := range expected {
+				if regions[i] != expected[i] {
+					t.Errorf("region %d = %+v, want %+v", i, regions[i], expected[i])
+				}
+			}
+		})
+	}
+}
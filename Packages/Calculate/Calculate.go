@@ -1,136 +1,143 @@
 package Calculate
 
 import (
-	"debug/pe"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"math"
-	"os"
-	"strings"
 )
 
+// Kinds of regions a binary is made of
+const (
+	KindSection = "section"
+	KindSegment = "segment"
+	KindSlice   = "slice"
+	KindHeader  = "header"
+	KindOverlay = "overlay"
+	KindGap     = "gap"
+)
+
+// Region struct
+type Region struct {
+	Name            string // Region name
+	Kind            string // Section, segment, slice, header or overlay
+	Offset          int64  // File offset of the region
+	Size            int64  // Size of the region in the file in bytes
+	VirtualSize     int64  // Size of the region in memory
+	Characteristics uint32 // Format-specific region flags
+}
+
 // SectionEntropy struct
 type SectionEntropy struct {
-	Name    string  // Section name
+	Region
 	Entropy float64 // Calculated entropy value
-	Size    int64   // Size of the section in bytes
-	Offset  int64   // File offset of the section
 }
 
 // CalculateFullEntropy function
 func CalculateFullEntropy(buffer []byte) float64 {
-	entropy := 0.0
+	return NewHistogram(buffer).Entropy()
+}
 
-	// Create a map to count byte occurrences
-	counts := make(map[byte]int)
-	for _, b := range buffer {
-		counts[b]++
-	}
+// CalculateSectionEntropy function
+func CalculateSectionEntropy(data []byte) float64 {
+	return NewHistogram(data).Entropy()
+}
 
-	// Calculate entropy using Shannon's formula
-	bufferLen := float64(len(buffer))
-	for _, count := range counts {
-		p := float64(count) / bufferLen
-		entropy += -p * math.Log2(p)
+// mixedEntropy function
+// mixedEntropy returns the expected entropy of the histogram after appending
+// padding bytes drawn from distribution.
+func mixedEntropy(histogram *Histogram, total uint64, distribution [256]float64, padding uint64) float64 {
+	entropy := 0.0
+	mixedTotal := float64(total + padding)
+	for i, count := range histogram {
+		mixedCount := float64(count) + float64(padding)*distribution[i]
+		if mixedCount <= 0 {
+			continue
+		}
+		p := mixedCount / mixedTotal
+		entropy -= p * math.Log2(p)
 	}
 
 	return entropy
 }
 
-// CalculateSectionEntropy function
-func CalculateSectionEntropy(data []byte) float64 {
-	// Handle empty data case
-	if len(data) == 0 {
-		return 0
-	}
+// PredictEntropy function
+// PredictEntropy returns the expected entropy of histogram after appending
+// padding bytes drawn from distribution, without generating them.
+func PredictEntropy(histogram *Histogram, distribution [256]float64, padding uint64) float64 {
+	return math.Min(math.Max(mixedEntropy(histogram, histogram.Total(), distribution, padding), 0), 8)
+}
 
-	var (
-		byteCounts [256]int
-		dataLength = float64(len(data))
-		entropy    float64
-	)
+// DistributionEntropy function
+// DistributionEntropy returns the entropy of bytes drawn from distribution.
+func DistributionEntropy(distribution [256]float64) float64 {
+	return PredictEntropy(&Histogram{}, distribution, 1)
+}
 
-	// Count occurrences of each byte value
-	for _, b := range data {
-		byteCounts[b]++
+// SolvePaddingSize function
+// SolvePaddingSize returns the smallest number of padding bytes, drawn from
+// distribution, that lowers the entropy of histogram to target or below.
+// Entropy is concave along the mixing line, so once the padded entropy drops
+// below target it stays there and a binary search finds the exact size.
+func SolvePaddingSize(histogram *Histogram, distribution [256]float64, target float64) (uint64, error) {
+	total := histogram.Total()
+
+	// Nothing to do if the data is already at or below target
+	if mixedEntropy(histogram, total, distribution, 0) <= target {
+		return 0, nil
 	}
 
-	// Calculate Shannon entropy
-	for _, count := range byteCounts {
-		if count > 0 {
-			probability := float64(count) / dataLength
-			entropy -= probability * math.Log2(probability)
+	// The padded entropy converges to the entropy of the distribution itself
+	limit := 0.0
+	for _, p := range distribution {
+		if p > 0 {
+			limit -= p * math.Log2(p)
 		}
 	}
+	if limit >= target {
+		return 0, fmt.Errorf("target entropy %.5f is unreachable, strategy entropy is %.5f", target, limit)
+	}
 
-	// Ensure entropy stays within valid range
-	if entropy < 0 {
-		log.Printf("Warning: Negative entropy calculated, adjusting to 0")
-		return 0
+	// Grow the upper bound until it satisfies the target
+	high := total
+	if high == 0 {
+		high = 1
 	}
-	if entropy > 8 {
-		log.Printf("Warning: Entropy exceeded maximum possible value, adjusting to 8")
-		return 8
+	for mixedEntropy(histogram, total, distribution, high) > target {
+		if high > math.MaxUint64/2 {
+			return 0, errors.New("padding size overflow while solving for target entropy")
+		}
+		high *= 2
 	}
 
-	return entropy
-}
-
-// ReadSections function
-func ReadSections(filePath string) ([]SectionEntropy, error) {
-	// Open the PE file
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+	// Binary search the smallest padding size that reaches the target
+	low := uint64(0)
+	for low+1 < high {
+		middle := low + (high-low)/2
+		if mixedEntropy(histogram, total, distribution, middle) <= target {
+			high = middle
+		} else {
+			low = middle
+		}
 	}
-	defer file.Close()
 
-	// Parse the PE file structure
-	peFile, err := pe.NewFile(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse PE file: %w", err)
-	}
+	return high, nil
+}
 
+// AnalyzeRegions function
+// AnalyzeRegions streams every region of r through a bounded buffer and
+// computes its entropy.
+func AnalyzeRegions(r io.ReaderAt, regions []Region) ([]SectionEntropy, error) {
 	var sectionEntropies []SectionEntropy
 
-	// Process each section
-	for _, section := range peFile.Sections {
-		// Skip sections with zero size
-		if section.Size == 0 {
-			log.Printf("Warning: Skipping zero-size section %s\n", section.Name)
-			continue
-		}
-
-		// Allocate buffer for section data
-		rawData := make([]byte, section.Size)
-
-		// Read section data using correct file offset
-		n, err := file.ReadAt(rawData, int64(section.Offset))
+	for _, region := range regions {
+		// Call function named streamEntropy
+		entropy, _, err := streamEntropy(r, region.Offset, region.Size)
 		if err != nil {
-			// Special handling for .reloc section which often has special requirements
-			if strings.EqualFold(section.Name, ".reloc") {
-				log.Printf("Warning: Incomplete read of .reloc section: %v\n", err)
-				if n == 0 {
-					continue
-				}
-				// Use partial data if some was read
-				rawData = rawData[:n]
-			} else {
-				// For other sections, report the error
-				return nil, fmt.Errorf("failed to read section %s: %w", section.Name, err)
-			}
+			return nil, fmt.Errorf("failed to read %s %s: %w", region.Kind, region.Name, err)
 		}
 
-		// Call function named CalculateSectionEntropy
-		entropy := CalculateSectionEntropy(rawData[:n])
-
-		// Store section information
-		sectionEntropies = append(sectionEntropies, SectionEntropy{
-			Name:    string(section.Name),
-			Entropy: entropy,
-			Size:    int64(n),
-			Offset:  int64(section.Offset),
-		})
+		sectionEntropies = append(sectionEntropies, SectionEntropy{Region: region, Entropy: entropy})
 	}
 
 	return sectionEntropies, nil
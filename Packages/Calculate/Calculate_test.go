@@ -0,0 +1,111 @@
+package Calculate
+
+import (
+	"SugarFree/Packages/Reduce"
+	"math/rand"
+	"testing"
+)
+
+// testHistogram returns the histogram of size bytes drawn by fill
+func testHistogram(size int, fill func(rng *rand.Rand) byte) *Histogram {
+	rng := rand.New(rand.NewSource(1))
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = fill(rng)
+	}
+
+	return NewHistogram(data)
+}
+
+// TestSolvePaddingSize function
+// TestSolvePaddingSize checks that the solved size is the smallest one that
+// reaches the target and that every larger size stays at or below it.
+func TestSolvePaddingSize(t *testing.T) {
+	pattern, err := Reduce.NewPatternStrategy([]byte{0x90, 0xcc, 0x90, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	word, err := Reduce.NewStrategy("word")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero, err := Reduce.NewStrategy("zero")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Random data, and code-like data with a skewed distribution whose
+	// entropy first rises when mixed with letters
+	random := testHistogram(1<<16, func(rng *rand.Rand) byte { return byte(rng.Intn(256)) })
+	skewed := testHistogram(1<<16, func(rng *rand.Rand) byte {
+		if rng.Intn(3) == 0 {
+			return byte(rng.Intn(256))
+		}
+		return byte(rng.Intn(8))
+	})
+
+	tests := []struct {
+		name      string
+		histogram *Histogram
+		strategy  Reduce.Strategy
+		target    float64
+	}{
+		{"zero on random data", random, zero, 4.6},
+		{"zero on random data near the strategy entropy", random, zero, 0.01},
+		{"zero on skewed data", skewed, zero, 3},
+		{"pattern on random data", random, pattern, 4.6},
+		{"pattern on random data near the strategy entropy", random, pattern, 1.6},
+		{"pattern on skewed data", skewed, pattern, 2},
+		{"word on random data", random, word, 6},
+		{"word on random data near the strategy entropy", random, word, 4.3},
+		{"word on skewed data", skewed, word, 4.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			distribution := test.strategy.Distribution()
+
+			size, err := SolvePaddingSize(test.histogram, distribution, test.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size == 0 {
+				t.Fatalf("solved no padding for target %.5f above entropy %.5f", test.target, test.histogram.Entropy())
+			}
+
+			// The solved size is the smallest one reaching the target
+			if entropy := PredictEntropy(test.histogram, distribution, size); entropy > test.target {
+				t.Errorf("entropy %.5f at %d bytes is above target %.5f", entropy, size, test.target)
+			}
+			if entropy := PredictEntropy(test.histogram, distribution, size-1); entropy <= test.target {
+				t.Errorf("entropy %.5f at %d bytes already reaches target %.5f", entropy, size-1, test.target)
+			}
+
+			// Every larger size stays at or below the target
+			for padding := size; padding < size*64; padding += size/8 + 1 {
+				if entropy := PredictEntropy(test.histogram, distribution, padding); entropy > test.target {
+					t.Fatalf("entropy %.5f at %d bytes rises above target %.5f again", entropy, padding, test.target)
+				}
+			}
+		})
+	}
+}
+
+// TestSolvePaddingSizeLimits function
+// TestSolvePaddingSizeLimits checks data already below the target and
+// targets below the entropy of the strategy itself.
+func TestSolvePaddingSizeLimits(t *testing.T) {
+	word, err := Reduce.NewStrategy("word")
+	if err != nil {
+		t.Fatal(err)
+	}
+	distribution := word.Distribution()
+	random := testHistogram(1<<12, func(rng *rand.Rand) byte { return byte(rng.Intn(256)) })
+
+	if size, err := SolvePaddingSize(random, distribution, 8); err != nil || size != 0 {
+		t.Errorf("SolvePaddingSize at or below target = %d, %v, want 0, nil", size, err)
+	}
+	if _, err := SolvePaddingSize(random, distribution, DistributionEntropy(distribution)-0.01); err == nil {
+		t.Error("SolvePaddingSize below the strategy entropy succeeded, want an error")
+	}
+}
@@ -0,0 +1,121 @@
+package Calculate
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// elfBinary struct
+type elfBinary struct {
+	binaryLayout
+	file *elf.File
+}
+
+// newELFBinary function
+// newELFBinary maps every ELF section with file data, every segment from the
+// program header table, the headers and any data appended after the last
+// known structure.
+func newELFBinary(r io.ReaderAt, size int64) (*elfBinary, error) {
+	// Parse the ELF file structure
+	elfFile, err := elf.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ELF file: %w", err)
+	}
+
+	binary := &elfBinary{binaryLayout: binaryLayout{format: FormatELF, size: size, reader: r}, file: elfFile}
+
+	// Process each section with file data
+	for _, section := range elfFile.Sections {
+		if section.Type == elf.SHT_NOBITS || section.Type == elf.SHT_NULL {
+			continue
+		}
+
+		binary.addRegion(Region{
+			Name:            section.Name,
+			Kind:            KindSection,
+			Offset:          int64(section.Offset),
+			Size:            int64(section.FileSize),
+			VirtualSize:     int64(section.Size),
+			Characteristics: uint32(section.Flags),
+		})
+	}
+
+	// Process each segment
+	for i, prog := range elfFile.Progs {
+		binary.addRegion(Region{
+			Name:            fmt.Sprintf("%s#%d", prog.Type, i),
+			Kind:            KindSegment,
+			Offset:          int64(prog.Off),
+			Size:            int64(prog.Filesz),
+			VirtualSize:     int64(prog.Memsz),
+			Characteristics: uint32(prog.Flags),
+		})
+	}
+
+	headersEnd := size
+	contentEnd := int64(0)
+	for _, region := range binary.regions {
+		if region.Kind == KindSection {
+			headersEnd = min(headersEnd, region.Offset)
+		}
+		contentEnd = max(contentEnd, region.Offset+region.Size)
+	}
+
+	// The section header table usually sits at the very end
+	if sectionTableEnd, ok := elfSectionTableEnd(r); ok {
+		contentEnd = max(contentEnd, min(sectionTableEnd, size))
+	}
+
+	// Headers precede the first section
+	if headersEnd > 0 && headersEnd < size {
+		binary.headers = []Region{{Name: "<headers>", Kind: KindHeader, Offset: 0, Size: headersEnd}}
+	}
+
+	// Overlay follows everything the headers describe
+	binary.setOverlay(contentEnd)
+
+	return binary, nil
+}
+
+// elfSectionTableEnd function
+// elfSectionTableEnd returns the end offset of the section header table.
+func elfSectionTableEnd(r io.ReaderAt) (int64, bool) {
+	header := make([]byte, 64)
+	if _, err := r.ReadAt(header[:elf.EI_NIDENT], 0); err != nil {
+		return 0, false
+	}
+
+	// Pick the byte order and header layout from the identification bytes
+	var order binary.ByteOrder
+	switch elf.Data(header[elf.EI_DATA]) {
+	case elf.ELFDATA2LSB:
+		order = binary.LittleEndian
+	case elf.ELFDATA2MSB:
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	switch elf.Class(header[elf.EI_CLASS]) {
+	case elf.ELFCLASS32:
+		if _, err := r.ReadAt(header[:52], 0); err != nil {
+			return 0, false
+		}
+		shoff := int64(order.Uint32(header[32:]))
+		shentsize := int64(order.Uint16(header[46:]))
+		shnum := int64(order.Uint16(header[48:]))
+		return shoff + shentsize*shnum, shoff > 0
+	case elf.ELFCLASS64:
+		if _, err := r.ReadAt(header[:64], 0); err != nil {
+			return 0, false
+		}
+		shoff := int64(order.Uint64(header[40:]))
+		shentsize := int64(order.Uint16(header[58:]))
+		shnum := int64(order.Uint16(header[60:]))
+		return shoff + shentsize*shnum, shoff > 0
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,46 @@
+package Calculate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Binary formats recognised by DetectFormat
+const (
+	FormatPE             = "PE"
+	FormatELF            = "ELF"
+	FormatMachO          = "Mach-O"
+	FormatMachOUniversal = "Mach-O Universal"
+	FormatRaw            = "Raw"
+	FormatUnknown        = "Unknown"
+)
+
+// DetectFormat function
+// DetectFormat identifies the binary format of r from its magic bytes.
+func DetectFormat(r io.ReaderAt) string {
+	magic := make([]byte, 8)
+	n, _ := r.ReadAt(magic, 0)
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("MZ")):
+		return FormatPE
+	case bytes.HasPrefix(magic, []byte("\x7fELF")):
+		return FormatELF
+	case bytes.HasPrefix(magic, []byte{0xfe, 0xed, 0xfa, 0xce}),
+		bytes.HasPrefix(magic, []byte{0xfe, 0xed, 0xfa, 0xcf}),
+		bytes.HasPrefix(magic, []byte{0xce, 0xfa, 0xed, 0xfe}),
+		bytes.HasPrefix(magic, []byte{0xcf, 0xfa, 0xed, 0xfe}):
+		return FormatMachO
+	case bytes.HasPrefix(magic, []byte{0xca, 0xfe, 0xba, 0xbe}) && len(magic) == 8:
+		// Java class files share this magic, but store a class file version
+		// of at least 45 where universal files store a small slice count
+		if binary.BigEndian.Uint32(magic[4:]) < 45 {
+			return FormatMachOUniversal
+		}
+		return FormatUnknown
+	default:
+		return FormatUnknown
+	}
+}
@@ -0,0 +1,133 @@
+package Calculate
+
+import (
+	"errors"
+	"io"
+	"math"
+)
+
+// readBufferSize bounds the memory used when streaming data into a histogram
+const readBufferSize = 64 * 1024
+
+// Histogram type
+// Histogram counts the occurrences of each byte value so entropy can be
+// updated incrementally as data is added or removed.
+type Histogram [256]uint64
+
+// NewHistogram function
+func NewHistogram(data []byte) *Histogram {
+	histogram := &Histogram{}
+	histogram.Add(data)
+
+	return histogram
+}
+
+// Add function
+// Add counts every byte of data.
+func (h *Histogram) Add(data []byte) {
+	for _, b := range data {
+		h[b]++
+	}
+}
+
+// Remove function
+// Remove uncounts every byte of data, which must have been added before.
+func (h *Histogram) Remove(data []byte) {
+	for _, b := range data {
+		h[b]--
+	}
+}
+
+// Merge function
+// Merge adds the counts of another histogram.
+func (h *Histogram) Merge(other *Histogram) {
+	for i, count := range other {
+		h[i] += count
+	}
+}
+
+// Subtract function
+// Subtract removes the counts of another histogram, which must have been
+// merged or added before.
+func (h *Histogram) Subtract(other *Histogram) {
+	for i, count := range other {
+		h[i] -= count
+	}
+}
+
+// Total function
+// Total returns the number of counted bytes.
+func (h *Histogram) Total() uint64 {
+	var total uint64
+	for _, count := range h {
+		total += count
+	}
+
+	return total
+}
+
+// Entropy function
+// Entropy returns the Shannon entropy of the counted bytes in bits per byte.
+func (h *Histogram) Entropy() float64 {
+	total := float64(h.Total())
+	if total == 0 {
+		return 0
+	}
+
+	entropy := 0.0
+	for _, count := range h {
+		if count > 0 {
+			p := float64(count) / total
+			entropy -= p * math.Log2(p)
+		}
+	}
+
+	// Ensure entropy stays within valid range despite rounding
+	return math.Min(math.Max(entropy, 0), 8)
+}
+
+// HistogramFromReader function
+// HistogramFromReader streams r into a new histogram using a bounded buffer.
+func HistogramFromReader(r io.Reader) (*Histogram, error) {
+	histogram := &Histogram{}
+	buffer := make([]byte, readBufferSize)
+
+	for {
+		n, err := r.Read(buffer)
+		histogram.Add(buffer[:n])
+		if errors.Is(err, io.EOF) {
+			return histogram, nil
+		}
+		if err != nil {
+			return histogram, err
+		}
+	}
+}
+
+// EntropyFromReader function
+// EntropyFromReader returns the entropy of everything read from r.
+func EntropyFromReader(r io.Reader) (float64, error) {
+	histogram, err := HistogramFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return histogram.Entropy(), nil
+}
+
+// streamEntropy function
+// streamEntropy returns the entropy of size bytes of r starting at offset,
+// along with the number of bytes read.
+func streamEntropy(r io.ReaderAt, offset int64, size int64) (float64, int64, error) {
+	histogram, err := HistogramFromReader(io.NewSectionReader(r, offset, size))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	n := int64(histogram.Total())
+	if n < size {
+		return 0, n, io.ErrUnexpectedEOF
+	}
+
+	return histogram.Entropy(), n, nil
+}
@@ -0,0 +1,173 @@
+package Calculate
+
+import (
+	"debug/macho"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// machoSlice describes one architecture inside a Mach-O file
+type machoSlice struct {
+	prefix string      // Name prefix for the slice regions, empty for thin files
+	offset int64       // File offset of the slice
+	size   int64       // Size of the slice in bytes
+	file   *macho.File // Parsed slice
+}
+
+// machoZerofill reports whether a section type has no file data
+func machoZerofill(flags uint32) bool {
+	switch flags & 0xff {
+	case 0x01, 0x0c, 0x12: // S_ZEROFILL, S_GB_ZEROFILL, S_THREAD_LOCAL_ZEROFILL
+		return true
+	default:
+		return false
+	}
+}
+
+// MachOArchName function
+// MachOArchName returns the conventional name of a Mach-O CPU type.
+func MachOArchName(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.Cpu386:
+		return "i386"
+	case macho.CpuAmd64:
+		return "x86_64"
+	case macho.CpuArm:
+		return "arm"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.CpuPpc:
+		return "ppc"
+	case macho.CpuPpc64:
+		return "ppc64"
+	default:
+		return fmt.Sprintf("cpu%d", uint32(cpu))
+	}
+}
+
+// readMachOSlices function
+// readMachOSlices parses a thin Mach-O file as a single slice, or every
+// architecture of a universal file as its own slice.
+func readMachOSlices(r io.ReaderAt, size int64) ([]machoSlice, error) {
+	if DetectFormat(r) == FormatMachOUniversal {
+		fatFile, err := macho.NewFatFile(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse universal Mach-O file: %w", err)
+		}
+
+		var slices []machoSlice
+		for _, arch := range fatFile.Arches {
+			slices = append(slices, machoSlice{
+				prefix: MachOArchName(arch.Cpu) + ":",
+				offset: int64(arch.Offset),
+				size:   int64(arch.Size),
+				file:   arch.File,
+			})
+		}
+
+		return slices, nil
+	}
+
+	machoFile, err := macho.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Mach-O file: %w", err)
+	}
+
+	return []machoSlice{{offset: 0, size: size, file: machoFile}}, nil
+}
+
+// machoBinary struct
+type machoBinary struct {
+	binaryLayout
+	slices []machoSlice
+}
+
+// newMachOBinary function
+// newMachOBinary maps every slice, segment and section of a thin or universal
+// Mach-O file together with the headers and any data appended after the last
+// segment. Offsets are absolute within the file.
+func newMachOBinary(r io.ReaderAt, size int64, format string) (*machoBinary, error) {
+	// Call function named readMachOSlices
+	slices, err := readMachOSlices(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	binary := &machoBinary{binaryLayout: binaryLayout{format: format, size: size, reader: r}, slices: slices}
+	contentEnd := int64(0)
+
+	for _, slice := range slices {
+		// Universal files also report every slice as a whole
+		if slice.prefix != "" {
+			binary.addRegion(Region{Name: MachOArchName(slice.file.Cpu), Kind: KindSlice, Offset: slice.offset, Size: slice.size})
+		}
+
+		headersEnd := slice.offset + slice.size
+		sliceEnd := slice.offset
+
+		// Process each segment, segments such as __LINKEDIT hold data
+		// outside any section
+		for _, load := range slice.file.Loads {
+			segment, ok := load.(*macho.Segment)
+			if !ok || segment.Filesz == 0 {
+				continue
+			}
+
+			offset := slice.offset + int64(segment.Offset)
+			binary.addRegion(Region{
+				Name:            slice.prefix + segment.Name,
+				Kind:            KindSegment,
+				Offset:          offset,
+				Size:            int64(segment.Filesz),
+				VirtualSize:     int64(segment.Memsz),
+				Characteristics: segment.Prot,
+			})
+			sliceEnd = max(sliceEnd, min(offset+int64(segment.Filesz), size))
+		}
+
+		// Process each section
+		for _, section := range slice.file.Sections {
+			if section.Size == 0 || section.Offset == 0 || machoZerofill(section.Flags) {
+				continue
+			}
+
+			offset := slice.offset + int64(section.Offset)
+			binary.addRegion(Region{
+				Name:            fmt.Sprintf("%s%s,%s", slice.prefix, section.Seg, section.Name),
+				Kind:            KindSection,
+				Offset:          offset,
+				Size:            int64(section.Size),
+				VirtualSize:     int64(section.Size),
+				Characteristics: section.Flags,
+			})
+			if offset < size {
+				headersEnd = min(headersEnd, offset)
+				sliceEnd = max(sliceEnd, min(offset+int64(section.Size), size))
+			}
+		}
+
+		// Headers and load commands precede the first section
+		if headersEnd > slice.offset && headersEnd <= size {
+			binary.headers = append(binary.headers, Region{Name: slice.prefix + "<headers>", Kind: KindHeader, Offset: slice.offset, Size: headersEnd - slice.offset})
+		}
+
+		contentEnd = max(contentEnd, sliceEnd)
+	}
+
+	// Universal files start with the fat header
+	if len(slices) > 0 && slices[0].prefix != "" {
+		firstSlice := slices[0].offset
+		for _, slice := range slices {
+			firstSlice = min(firstSlice, slice.offset)
+		}
+		binary.headers = append(binary.headers, Region{Name: "<fat header>", Kind: KindHeader, Offset: 0, Size: firstSlice})
+	}
+
+	sort.Slice(binary.headers, func(i, j int) bool { return binary.headers[i].Offset < binary.headers[j].Offset })
+
+	// Overlay follows the last segment
+	binary.setOverlay(contentEnd)
+
+	return binary, nil
+}
@@ -0,0 +1,123 @@
+package Calculate
+
+import (
+	"compress/flate"
+	"io"
+	"math"
+)
+
+// Metrics struct
+type Metrics struct {
+	ConditionalEntropy float64 // Order-1 entropy of a byte given the previous one in bits per byte
+	RenyiEntropy       float64 // Order-2 (collision) Rényi entropy in bits per byte
+	MinEntropy         float64 // Min-entropy in bits per byte
+	CompressionRatio   float64 // DEFLATE compressed size divided by the original size
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int64
+}
+
+// Write function
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// MetricsWriter struct
+// MetricsWriter accumulates the alternative randomness metrics of everything
+// written to it, so data of any size is processed in a single stream.
+type MetricsWriter struct {
+	histogram  Histogram
+	pairs      []uint64 // Counts of every byte pair indexed by previous<<8 | current
+	started    bool
+	last       byte
+	compressed countingWriter
+	compressor *flate.Writer
+}
+
+// NewMetricsWriter function
+func NewMetricsWriter() *MetricsWriter {
+	writer := &MetricsWriter{pairs: make([]uint64, 256*256)}
+	writer.compressor, _ = flate.NewWriter(&writer.compressed, flate.DefaultCompression)
+
+	return writer
+}
+
+// Write function
+// Write accumulates p and never fails.
+func (w *MetricsWriter) Write(p []byte) (int, error) {
+	w.histogram.Add(p)
+
+	for _, b := range p {
+		if w.started {
+			w.pairs[int(w.last)<<8|int(b)]++
+		}
+		w.started = true
+		w.last = b
+	}
+
+	return w.compressor.Write(p)
+}
+
+// Metrics function
+// Metrics flushes the compressor and returns the metrics of everything
+// written, no data may be written afterwards.
+func (w *MetricsWriter) Metrics() Metrics {
+	var metrics Metrics
+	w.compressor.Close()
+
+	total := float64(w.histogram.Total())
+	if total == 0 {
+		return metrics
+	}
+
+	// Rényi and min-entropy from the byte probabilities
+	collision := 0.0
+	maxProbability := 0.0
+	for _, count := range w.histogram {
+		p := float64(count) / total
+		collision += p * p
+		maxProbability = math.Max(maxProbability, p)
+	}
+	metrics.RenyiEntropy = math.Max(-math.Log2(collision), 0)
+	metrics.MinEntropy = math.Max(-math.Log2(maxProbability), 0)
+
+	// Conditional entropy weighs the entropy of every successor distribution
+	pairTotal := total - 1
+	for previous := 0; previous < 256; previous++ {
+		successors := w.pairs[previous<<8 : previous<<8+256]
+		predecessorCount := 0.0
+		for _, count := range successors {
+			predecessorCount += float64(count)
+		}
+
+		for _, count := range successors {
+			if count > 0 {
+				metrics.ConditionalEntropy -= float64(count) / pairTotal * math.Log2(float64(count)/predecessorCount)
+			}
+		}
+	}
+	metrics.ConditionalEntropy = math.Min(math.Max(metrics.ConditionalEntropy, 0), 8)
+
+	metrics.CompressionRatio = float64(w.compressed.n) / total
+
+	return metrics
+}
+
+// MetricsAt function
+// MetricsAt streams size bytes of r starting at offset and returns their
+// alternative randomness metrics.
+func MetricsAt(r io.ReaderAt, offset int64, size int64) (Metrics, error) {
+	writer := NewMetricsWriter()
+	n, err := io.CopyBuffer(writer, io.NewSectionReader(r, offset, size), make([]byte, readBufferSize))
+	if err != nil {
+		return Metrics{}, err
+	}
+	if n < size {
+		return Metrics{}, io.ErrUnexpectedEOF
+	}
+
+	return writer.Metrics(), nil
+}
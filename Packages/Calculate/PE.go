@@ -0,0 +1,87 @@
+package Calculate
+
+import (
+	"debug/pe"
+	"fmt"
+	"io"
+)
+
+// peBinary struct
+type peBinary struct {
+	binaryLayout
+	file *pe.File
+}
+
+// newPEBinary function
+// newPEBinary maps the headers, every section with raw data and the overlay
+// appended after the last section of a PE file.
+func newPEBinary(r io.ReaderAt, size int64) (*peBinary, error) {
+	// Parse the PE file structure
+	peFile, err := pe.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PE file: %w", err)
+	}
+
+	binary := &peBinary{binaryLayout: binaryLayout{format: FormatPE, size: size, reader: r}, file: peFile}
+	headersEnd := size
+	sectionsEnd := int64(0)
+
+	// Process each section, sections that claim more data than the file
+	// holds (commonly .reloc) are clamped to the data available
+	for _, section := range peFile.Sections {
+		binary.addRegion(Region{
+			Name:            section.Name,
+			Kind:            KindSection,
+			Offset:          int64(section.Offset),
+			Size:            int64(section.Size),
+			VirtualSize:     int64(section.VirtualSize),
+			Characteristics: section.Characteristics,
+		})
+	}
+
+	for _, region := range binary.regions {
+		headersEnd = min(headersEnd, region.Offset)
+		sectionsEnd = max(sectionsEnd, region.Offset+region.Size)
+	}
+
+	// Headers precede the first section
+	if headersEnd > 0 {
+		binary.headers = []Region{{Name: "<headers>", Kind: KindHeader, Offset: 0, Size: headersEnd}}
+	}
+
+	// Overlay follows the last section
+	binary.setOverlay(sectionsEnd)
+
+	return binary, nil
+}
+
+// rvaToOffset function
+// rvaToOffset maps a relative virtual address to its file offset.
+func (b *peBinary) rvaToOffset(rva uint32) (int64, bool) {
+	for _, section := range b.file.Sections {
+		size := max(section.VirtualSize, section.Size)
+		if rva >= section.VirtualAddress && rva < section.VirtualAddress+size {
+			offset := int64(section.Offset) + int64(rva-section.VirtualAddress)
+			return offset, rva-section.VirtualAddress < section.Size && offset < b.size
+		}
+	}
+
+	return 0, false
+}
+
+// dataDirectory function
+// dataDirectory returns an entry of the optional header data directory.
+func (b *peBinary) dataDirectory(index int) pe.DataDirectory {
+	switch header := b.file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		if uint32(index) < header.NumberOfRvaAndSizes {
+			return header.DataDirectory[index]
+		}
+	case *pe.OptionalHeader64:
+		if uint32(index) < header.NumberOfRvaAndSizes {
+			return header.DataDirectory[index]
+		}
+	}
+
+	return pe.DataDirectory{}
+}
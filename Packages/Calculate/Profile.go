@@ -0,0 +1,106 @@
+package Calculate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WindowEntropy struct
+type WindowEntropy struct {
+	Offset  int64   // File offset of the window
+	Size    int64   // Size of the window in bytes
+	Entropy float64 // Calculated entropy value
+	Region  string  // Regions the window overlaps
+}
+
+// EntropyProfile function
+// EntropyProfile slides a window of the given size over the first size bytes
+// of r, moving it by step bytes each time. Overlapping windows only add and
+// remove the bytes that entered and left, so each step costs O(step).
+func EntropyProfile(r io.ReaderAt, size int64, window int64, step int64) ([]WindowEntropy, error) {
+	if window <= 0 || step <= 0 {
+		return nil, fmt.Errorf("window and step must be positive, got %d and %d", window, step)
+	}
+
+	var profile []WindowEntropy
+	if size <= 0 {
+		return profile, nil
+	}
+
+	histogram := &Histogram{}
+	buffer := make([]byte, readBufferSize)
+
+	// Fill the first window
+	end := min(window, size)
+	if err := updateHistogram(histogram, r, 0, end, buffer, false); err != nil {
+		return nil, err
+	}
+	profile = append(profile, WindowEntropy{Offset: 0, Size: end, Entropy: histogram.Entropy()})
+
+	// Stop once a step jumps past the data, windows never start beyond it
+	for offset := step; end < size && offset < size; offset += step {
+		newEnd := min(offset+window, size)
+
+		if step >= window {
+			// Windows do not overlap, so start from an empty histogram
+			histogram = &Histogram{}
+			if err := updateHistogram(histogram, r, offset, newEnd, buffer, false); err != nil {
+				return nil, err
+			}
+		} else {
+			// Drop the bytes that left the window and add the ones that entered
+			if err := updateHistogram(histogram, r, offset-step, offset, buffer, true); err != nil {
+				return nil, err
+			}
+			if err := updateHistogram(histogram, r, end, newEnd, buffer, false); err != nil {
+				return nil, err
+			}
+		}
+
+		end = newEnd
+		profile = append(profile, WindowEntropy{Offset: offset, Size: end - offset, Entropy: histogram.Entropy()})
+	}
+
+	return profile, nil
+}
+
+// updateHistogram function
+// updateHistogram adds or removes the bytes of r between start and end.
+func updateHistogram(histogram *Histogram, r io.ReaderAt, start int64, end int64, buffer []byte, remove bool) error {
+	for start < end {
+		chunk := buffer[:min(int64(len(buffer)), end-start)]
+		n, err := r.ReadAt(chunk, start)
+		if n < len(chunk) {
+			if err == nil || errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("failed to read window at offset %d: %w", start, err)
+		}
+
+		if remove {
+			histogram.Remove(chunk)
+		} else {
+			histogram.Add(chunk)
+		}
+		start += int64(n)
+	}
+
+	return nil
+}
+
+// AnnotateProfile function
+// AnnotateProfile labels every window with the names of the regions it overlaps.
+func AnnotateProfile(profile []WindowEntropy, regions []Region) {
+	for i := range profile {
+		var names []string
+		windowEnd := profile[i].Offset + profile[i].Size
+		for _, region := range regions {
+			if region.Offset < windowEnd && profile[i].Offset < region.Offset+region.Size {
+				names = append(names, region.Name)
+			}
+		}
+		profile[i].Region = strings.Join(names, "+")
+	}
+}
@@ -0,0 +1,237 @@
+package Calculate
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+)
+
+// maxResourceDepth bounds the directory walk, resource trees are three levels deep
+const maxResourceDepth = 8
+
+// resourceTypes maps predefined resource type IDs to their names
+var resourceTypes = map[uint32]string{
+	1:  "RT_CURSOR",
+	2:  "RT_BITMAP",
+	3:  "RT_ICON",
+	4:  "RT_MENU",
+	5:  "RT_DIALOG",
+	6:  "RT_STRING",
+	7:  "RT_FONTDIR",
+	8:  "RT_FONT",
+	9:  "RT_ACCELERATOR",
+	10: "RT_RCDATA",
+	11: "RT_MESSAGETABLE",
+	12: "RT_GROUP_CURSOR",
+	14: "RT_GROUP_ICON",
+	16: "RT_VERSION",
+	17: "RT_DLGINCLUDE",
+	19: "RT_PLUGPLAY",
+	20: "RT_VXD",
+	21: "RT_ANICURSOR",
+	22: "RT_ANIICON",
+	23: "RT_HTML",
+	24: "RT_MANIFEST",
+}
+
+// embeddedMagics lists the signatures of executables and archives worth flagging
+var embeddedMagics = []struct {
+	name  string
+	magic []byte
+}{
+	{"PE", []byte("MZ")},
+	{"ELF", []byte("\x7fELF")},
+	{"ZIP", []byte("PK\x03\x04")},
+	{"7Z", []byte("7z\xbc\xaf\x27\x1c")},
+	{"RAR", []byte("Rar!\x1a\x07")},
+	{"GZIP", []byte("\x1f\x8b")},
+	{"CAB", []byte("MSCF")},
+}
+
+// Resource struct
+type Resource struct {
+	Type     string  // Resource type name or ID
+	Name     string  // Resource name or ID
+	Language string  // Language ID
+	Offset   int64   // File offset of the resource data
+	Size     int64   // Size of the resource data in bytes
+	Entropy  float64 // Calculated entropy value
+	Magic    string  // Embedded executable or archive format, empty if none
+}
+
+// resourceWalker walks the resource directory of a PE file
+type resourceWalker struct {
+	binary  *peBinary
+	base    int64 // File offset of the root directory
+	visited map[int64]bool
+	path    []string
+	found   []Resource
+}
+
+// ReadResources function
+// ReadResources walks the resource tree of a PE file and returns every
+// resource with its entropy and any embedded executable or archive magic.
+// Binaries of other formats have no resources.
+func ReadResources(binary Binary) ([]Resource, error) {
+	peFile, ok := binary.(*peBinary)
+	if !ok {
+		return nil, nil
+	}
+
+	// Locate the resource directory
+	directory := peFile.dataDirectory(pe.IMAGE_DIRECTORY_ENTRY_RESOURCE)
+	if directory.VirtualAddress == 0 || directory.Size == 0 {
+		return nil, nil
+	}
+
+	base, ok := peFile.rvaToOffset(directory.VirtualAddress)
+	if !ok {
+		return nil, fmt.Errorf("resource directory RVA 0x%x is outside the file", directory.VirtualAddress)
+	}
+
+	walker := &resourceWalker{binary: peFile, base: base, visited: map[int64]bool{}}
+	if err := walker.walk(0, 0); err != nil {
+		return nil, fmt.Errorf("failed to read resource directory: %w", err)
+	}
+
+	// Compute the entropy and magic of every resource
+	for i := range walker.found {
+		resource := &walker.found[i]
+
+		// Call function named streamEntropy
+		entropy, n, err := streamEntropy(peFile.reader, resource.Offset, resource.Size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource %s/%s: %w", resource.Type, resource.Name, err)
+		}
+		resource.Entropy = entropy
+		resource.Size = n
+
+		// Call function named detectEmbeddedMagic
+		resource.Magic = detectEmbeddedMagic(peFile.reader, resource.Offset, resource.Size)
+	}
+
+	return walker.found, nil
+}
+
+// walk function
+// walk reads the directory at offset, relative to the root directory, and
+// descends into every subdirectory until it reaches the data entries.
+func (w *resourceWalker) walk(offset int64, depth int) error {
+	if depth >= maxResourceDepth || w.visited[offset] {
+		return nil
+	}
+	w.visited[offset] = true
+
+	// IMAGE_RESOURCE_DIRECTORY
+	header := make([]byte, 16)
+	if _, err := w.binary.reader.ReadAt(header, w.base+offset); err != nil {
+		return err
+	}
+	entries := int(binary.LittleEndian.Uint16(header[12:])) + int(binary.LittleEndian.Uint16(header[14:]))
+
+	// IMAGE_RESOURCE_DIRECTORY_ENTRY
+	table := make([]byte, entries*8)
+	if _, err := w.binary.reader.ReadAt(table, w.base+offset+16); err != nil {
+		return err
+	}
+
+	for i := 0; i < entries; i++ {
+		nameField := binary.LittleEndian.Uint32(table[i*8:])
+		dataField := binary.LittleEndian.Uint32(table[i*8+4:])
+
+		// Call function named entryName
+		w.path = append(w.path, w.entryName(nameField, depth))
+
+		var err error
+		if dataField&0x80000000 != 0 {
+			err = w.walk(int64(dataField&0x7fffffff), depth+1)
+		} else {
+			err = w.addData(int64(dataField))
+		}
+
+		w.path = w.path[:len(w.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// entryName function
+// entryName resolves a directory entry to its string name or numeric ID,
+// using the predefined type names at the top level.
+func (w *resourceWalker) entryName(field uint32, depth int) string {
+	if field&0x80000000 == 0 {
+		if name, ok := resourceTypes[field]; ok && depth == 0 {
+			return name
+		}
+		return fmt.Sprintf("%d", field)
+	}
+
+	// IMAGE_RESOURCE_DIR_STRING_U
+	offset := w.base + int64(field&0x7fffffff)
+	length := make([]byte, 2)
+	if _, err := w.binary.reader.ReadAt(length, offset); err != nil {
+		return "?"
+	}
+	raw := make([]byte, int(binary.LittleEndian.Uint16(length))*2)
+	if _, err := w.binary.reader.ReadAt(raw, offset+2); err != nil {
+		return "?"
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(raw[i*2:])
+	}
+
+	return string(utf16.Decode(units))
+}
+
+// addData function
+// addData records the resource described by the data entry at offset.
+func (w *resourceWalker) addData(offset int64) error {
+	// IMAGE_RESOURCE_DATA_ENTRY
+	entry := make([]byte, 16)
+	if _, err := w.binary.reader.ReadAt(entry, w.base+offset); err != nil {
+		return err
+	}
+	rva := binary.LittleEndian.Uint32(entry[0:])
+	size := int64(binary.LittleEndian.Uint32(entry[4:]))
+
+	dataOffset, ok := w.binary.rvaToOffset(rva)
+	if !ok || size == 0 {
+		return nil
+	}
+
+	resource := Resource{Offset: dataOffset, Size: min(size, w.binary.size-dataOffset)}
+	if len(w.path) > 0 {
+		resource.Type = w.path[0]
+	}
+	if len(w.path) > 1 {
+		resource.Name = w.path[1]
+	}
+	if len(w.path) > 2 {
+		resource.Language = w.path[len(w.path)-1]
+	}
+	w.found = append(w.found, resource)
+
+	return nil
+}
+
+// detectEmbeddedMagic function
+// detectEmbeddedMagic returns the format whose magic starts the data at offset.
+func detectEmbeddedMagic(r io.ReaderAt, offset int64, size int64) string {
+	head := make([]byte, min(size, 8))
+	n, _ := r.ReadAt(head, offset)
+
+	for _, embedded := range embeddedMagics {
+		if bytes.HasPrefix(head[:n], embedded.magic) {
+			return embedded.name
+		}
+	}
+
+	return ""
+}
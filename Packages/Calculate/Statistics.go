@@ -0,0 +1,132 @@
+package Calculate
+
+import (
+	"io"
+	"math"
+)
+
+// monteCarloRadius is the squared radius of the Monte Carlo circle, points
+// are built from three bytes per coordinate
+const monteCarloRadius = float64(1<<24-1) * float64(1<<24-1)
+
+// Statistics struct
+type Statistics struct {
+	Histogram         Histogram // Byte histogram
+	ChiSquare         float64   // Chi-square statistic against a uniform distribution
+	Mean              float64   // Arithmetic mean of the bytes, 127.5 for random data
+	SerialCorrelation float64   // Correlation of every byte with the next, 0 for random data
+	MonteCarloPi      float64   // Monte Carlo estimate of pi
+	MonteCarloPiError float64   // Relative error of the estimate in percent
+}
+
+// StatisticsWriter struct
+// StatisticsWriter accumulates the distribution statistics of everything
+// written to it, so data of any size is processed in a single stream.
+type StatisticsWriter struct {
+	histogram Histogram
+
+	// Serial correlation terms
+	started bool
+	first   byte
+	last    byte
+	t1      float64
+	t2      float64
+	t3      float64
+
+	// Monte Carlo points of six bytes
+	group    [6]byte
+	grouped  int
+	inside   uint64
+	attempts uint64
+}
+
+// Write function
+// Write accumulates p and never fails.
+func (w *StatisticsWriter) Write(p []byte) (int, error) {
+	w.histogram.Add(p)
+
+	for _, b := range p {
+		c := float64(b)
+
+		// Serial correlation pairs every byte with its successor
+		if !w.started {
+			w.started = true
+			w.first = b
+		} else {
+			w.t1 += float64(w.last) * c
+		}
+		w.t2 += c * c
+		w.t3 += c
+		w.last = b
+
+		// Every six bytes form a point inside the unit square
+		w.group[w.grouped] = b
+		w.grouped++
+		if w.grouped == len(w.group) {
+			x := float64(uint32(w.group[0])<<16 | uint32(w.group[1])<<8 | uint32(w.group[2]))
+			y := float64(uint32(w.group[3])<<16 | uint32(w.group[4])<<8 | uint32(w.group[5]))
+			if x*x+y*y <= monteCarloRadius {
+				w.inside++
+			}
+			w.attempts++
+			w.grouped = 0
+		}
+	}
+
+	return len(p), nil
+}
+
+// Statistics function
+// Statistics returns the statistics of everything written so far.
+func (w *StatisticsWriter) Statistics() Statistics {
+	statistics := Statistics{Histogram: w.histogram}
+	total := float64(w.histogram.Total())
+	if total == 0 {
+		statistics.MonteCarloPiError = 100
+		return statistics
+	}
+
+	// Chi-square against the expected count of a uniform distribution
+	expected := total / 256
+	sum := 0.0
+	for value, count := range w.histogram {
+		difference := float64(count) - expected
+		statistics.ChiSquare += difference * difference / expected
+		sum += float64(value) * float64(count)
+	}
+	statistics.Mean = sum / total
+
+	// Serial correlation wraps the last byte around to the first, constant
+	// data is fully correlated
+	t1 := w.t1 + float64(w.last)*float64(w.first)
+	denominator := total*w.t2 - w.t3*w.t3
+	if denominator == 0 {
+		statistics.SerialCorrelation = 1
+	} else {
+		statistics.SerialCorrelation = (total*t1 - w.t3*w.t3) / denominator
+	}
+
+	// Monte Carlo estimate from the share of points inside the circle
+	if w.attempts > 0 {
+		statistics.MonteCarloPi = 4 * float64(w.inside) / float64(w.attempts)
+	}
+	statistics.MonteCarloPiError = math.Abs(statistics.MonteCarloPi-math.Pi) / math.Pi * 100
+
+	return statistics
+}
+
+// StatisticsAt function
+// StatisticsAt streams size bytes of r starting at offset and returns their
+// distribution statistics.
+func StatisticsAt(r io.ReaderAt, offset int64, size int64) (Statistics, error) {
+	writer := &StatisticsWriter{}
+	n, err := io.CopyBuffer(writer, io.NewSectionReader(r, offset, size), make([]byte, readBufferSize))
+	if err != nil {
+		return Statistics{}, err
+	}
+	if n < size {
+		return Statistics{}, io.ErrUnexpectedEOF
+	}
+
+	return writer.Statistics(), nil
+}
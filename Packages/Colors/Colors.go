@@ -20,6 +20,9 @@ var (
 	BoldCyan    = color.New(color.FgCyan, color.Bold).SprintFunc()
 )
 
+// EntropyThreshold separates low (green) from high (red) entropy values
+const EntropyThreshold = 5.0
+
 // Define a slice containing all available color functions
 var allColors = []func(a ...interface{}) string{
 	BoldBlue, BoldRed, BoldGreen, BoldYellow, BoldWhite, BoldMagenta, BoldCyan,
@@ -67,8 +70,8 @@ func ColorNameManager(section string) string {
 
 // CalculateColor2Entropy function
 func CalculateColor2Entropy(entropy float64) string {
-	// Check if the entropy is less than 5.0
-	if entropy < 5.0 {
+	// Check if the entropy is less than the threshold
+	if entropy < EntropyThreshold {
 		return BoldGreen(fmt.Sprintf("%.5f", entropy))
 	}
 
@@ -0,0 +1,211 @@
+package Graph
+
+import (
+	"SugarFree/Packages/Calculate"
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+var (
+	// Band colors
+	sectionColors = []color.Color{
+		color.NRGBA{R: 120, G: 170, B: 230, A: 70},
+		color.NRGBA{R: 140, G: 210, B: 150, A: 70},
+	}
+	headersColor = color.NRGBA{R: 150, G: 150, B: 150, A: 70}
+	overlayColor = color.NRGBA{R: 230, G: 90, B: 90, A: 110}
+
+	// Bar colors
+	lowColor  = color.RGBA{G: 160, B: 60, A: 255}
+	highColor = color.RGBA{R: 210, G: 40, B: 40, A: 255}
+)
+
+// EntropyProfile function
+// EntropyProfile plots windowed entropy against file offset, shading every
+// region as a band and drawing a dashed line at the threshold.
+func EntropyProfile(title string, profile []Calculate.WindowEntropy, regions []Calculate.Region, threshold float64) (*plot.Plot, error) {
+	// Create a new plot
+	p := plot.New()
+
+	p.Title.Text = title
+	p.X.Label.Text = "File Offset (bytes)"
+	p.Y.Label.Text = "Entropy"
+	p.Y.Min = 0
+
+	// Leave room above the maximum entropy for the legend
+	p.Y.Max = 9
+
+	// Add shaded bands for each region
+	var labelPoints plotter.XYs
+	var labelNames []string
+	for i, region := range regions {
+		start := float64(region.Offset)
+		end := float64(region.Offset + region.Size)
+		band, err := plotter.NewPolygon(plotter.XYs{{X: start, Y: 0}, {X: end, Y: 0}, {X: end, Y: 8}, {X: start, Y: 8}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create region band: %w", err)
+		}
+		band.LineStyle.Width = 0
+
+		switch region.Kind {
+		case Calculate.KindHeader, Calculate.KindGap:
+			band.Color = headersColor
+		case Calculate.KindOverlay:
+			band.Color = overlayColor
+		default:
+			band.Color = sectionColors[i%len(sectionColors)]
+		}
+		p.Add(band)
+
+		labelPoints = append(labelPoints, plotter.XY{X: start, Y: 0.1})
+		labelNames = append(labelNames, region.Name)
+	}
+
+	// Create points for the entropy line at the middle of each window
+	pts := make(plotter.XYs, len(profile))
+	for i, window := range profile {
+		pts[i].X = float64(window.Offset) + float64(window.Size)/2
+		pts[i].Y = window.Entropy
+	}
+
+	// Create a line plotter and set its style
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create line plot: %w", err)
+	}
+	line.Color = color.RGBA{R: 255, A: 255}
+	line.Width = vg.Points(1.5)
+	p.Add(line)
+
+	// Add the threshold line
+	thresholdLine := plotter.NewFunction(func(float64) float64 { return threshold })
+	thresholdLine.Color = color.RGBA{B: 255, A: 255}
+	thresholdLine.Dashes = []vg.Length{vg.Points(6), vg.Points(4)}
+	thresholdLine.Width = vg.Points(1)
+	p.Add(thresholdLine)
+	p.Legend.Add(fmt.Sprintf("Threshold (%.2f)", threshold), thresholdLine)
+	p.Legend.Add("Entropy", line)
+	p.Legend.Top = true
+
+	// Add region names rotated along the left edge of each band
+	if len(labelPoints) > 0 {
+		labels, err := plotter.NewLabels(plotter.XYLabels{XYs: labelPoints, Labels: labelNames})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create region labels: %w", err)
+		}
+		for i := range labels.TextStyle {
+			labels.TextStyle[i].Font.Size = vg.Points(7)
+			labels.TextStyle[i].Rotation = math.Pi / 2
+			labels.TextStyle[i].XAlign = draw.XLeft
+			labels.TextStyle[i].YAlign = draw.YTop
+		}
+		p.Add(labels)
+	}
+
+	return p, nil
+}
+
+// SectionEntropy function
+// SectionEntropy plots one bar per section, colored by the entropy threshold.
+func SectionEntropy(title string, names []string, entropies []float64, threshold float64) (*plot.Plot, error) {
+	// Create a new plot
+	p := plot.New()
+
+	p.Title.Text = title
+	p.Y.Label.Text = "Entropy"
+	p.Y.Min = 0
+	p.Y.Max = 8
+
+	// Split the values so each side of the threshold gets its own color
+	low := make(plotter.Values, len(entropies))
+	high := make(plotter.Values, len(entropies))
+	for i, entropy := range entropies {
+		if entropy < threshold {
+			low[i] = entropy
+		} else {
+			high[i] = entropy
+		}
+	}
+
+	for _, bars := range []struct {
+		values plotter.Values
+		color  color.Color
+	}{{low, lowColor}, {high, highColor}} {
+		barChart, err := plotter.NewBarChart(bars.values, vg.Points(14))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bar chart: %w", err)
+		}
+		barChart.Color = bars.color
+		barChart.LineStyle.Width = 0
+		p.Add(barChart)
+	}
+
+	// Add the threshold line
+	thresholdLine := plotter.NewFunction(func(float64) float64 { return threshold })
+	thresholdLine.Color = color.RGBA{B: 255, A: 255}
+	thresholdLine.Dashes = []vg.Length{vg.Points(6), vg.Points(4)}
+	p.Add(thresholdLine)
+
+	p.NominalX(names...)
+	p.X.Tick.Label.Rotation = math.Pi / 4
+	p.X.Tick.Label.XAlign = draw.XRight
+	p.X.Tick.Label.YAlign = draw.YCenter
+
+	return p, nil
+}
+
+// ByteHistogram function
+// ByteHistogram plots the number of occurrences of every byte value.
+func ByteHistogram(title string, histogram *Calculate.Histogram) (*plot.Plot, error) {
+	// Create a new plot
+	p := plot.New()
+
+	p.Title.Text = title
+	p.X.Label.Text = "Byte Value"
+	p.Y.Label.Text = "Occurrences"
+
+	// Create points for every byte value
+	pts := make(plotter.XYs, len(histogram))
+	for i, count := range histogram {
+		pts[i].X = float64(i)
+		pts[i].Y = float64(count)
+	}
+
+	// Draw the counts as a thin filled step line
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram plot: %w", err)
+	}
+	line.StepStyle = plotter.MidStep
+	line.FillColor = color.NRGBA{R: 120, G: 170, B: 230, A: 160}
+	line.Color = color.RGBA{B: 160, A: 255}
+	p.Add(line)
+	p.X.Min = 0
+	p.X.Max = 255
+	p.Y.Min = 0
+
+	return p, nil
+}
+
+// RenderSVG function
+// RenderSVG draws the plot into an in-memory SVG document.
+func RenderSVG(p *plot.Plot, width vg.Length, height vg.Length) ([]byte, error) {
+	writerTo, err := p.WriterTo(width, height, "svg")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render SVG: %w", err)
+	}
+
+	var buffer bytes.Buffer
+	if _, err := writerTo.WriteTo(&buffer); err != nil {
+		return nil, fmt.Errorf("failed to render SVG: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,176 @@
+package Output
+
+import (
+	"SugarFree/Packages/Calculate"
+	"SugarFree/Packages/Colors"
+	"SugarFree/Packages/Graph"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// htmlTemplate renders a single offline page, charts are embedded as data URIs
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"entropyClass": func(entropy float64) string {
+		if entropy < Colors.EntropyThreshold {
+			return "low"
+		}
+		return "high"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>SugarFree Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+th { background: #f0f0f0; }
+.low { color: #00a03c; font-weight: bold; }
+.high { color: #d22828; font-weight: bold; }
+code { font-size: 0.9em; }
+img { display: block; max-width: 100%; margin-bottom: 2em; }
+</style>
+</head>
+<body>
+{{range .Reports}}<h1>{{.Report.Format}} Analysis Report - {{.Report.File}}</h1>
+<table>
+<tr><td>File</td><td>{{.Report.File}}</td></tr>
+<tr><td>Format</td><td>{{.Report.Format}}</td></tr>
+<tr><td>Generated</td><td>{{.Report.GeneratedAt}}</td></tr>
+<tr><td>Size</td><td>{{.Report.Size}} bytes</td></tr>
+<tr><td>MD5</td><td><code>{{.Report.Hashes.MD5}}</code></td></tr>
+<tr><td>SHA1</td><td><code>{{.Report.Hashes.SHA1}}</code></td></tr>
+<tr><td>SHA256</td><td><code>{{.Report.Hashes.SHA256}}</code></td></tr>
+<tr><td>Overall Entropy{{if and .Report.OverlayExcluded .Report.Overlay}} (excluding overlay){{end}}</td><td class="{{entropyClass .Report.Entropy}}">{{printf "%.5f" .Report.Entropy}}</td></tr>
+{{if .Report.Overlay}}<tr><td>Overlay</td><td>{{printf "0x%08x" .Report.Overlay.Offset}}, {{.Report.Overlay.RawSize}} bytes ({{printf "%.2f" .Report.OverlayShare}}%), entropy <span class="{{entropyClass .Report.Overlay.Entropy}}">{{printf "%.5f" .Report.Overlay.Entropy}}</span></td></tr>
+{{end}}</table>
+<h2>Sections</h2>
+<table>
+<tr><th>Name</th><th>Kind</th><th>Offset</th><th>Raw Size</th><th>Virtual Size</th><th>Characteristics</th><th>Entropy</th></tr>
+{{range .Report.AllSections}}<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{printf "0x%08x" .Offset}}</td><td>{{.RawSize}}</td><td>{{.VirtualSize}}</td><td>{{printf "0x%08x" .Characteristics}}</td><td class="{{entropyClass .Entropy}}">{{printf "%.5f" .Entropy}}</td></tr>
+{{end}}</table>
+{{if .Report.Statistics}}<h2>Statistics</h2>
+<table>
+<tr><th>Name</th><th>Kind</th><th>Chi-Square</th><th>Mean</th><th>Serial Correlation</th><th>Pi Error</th></tr>
+<tr><td>{{.Report.File}}</td><td>file</td><td>{{printf "%.2f" .Report.Statistics.ChiSquare}}</td><td>{{printf "%.5f" .Report.Statistics.Mean}}</td><td>{{printf "%.6f" .Report.Statistics.SerialCorrelation}}</td><td>{{printf "%.2f" .Report.Statistics.MonteCarloPiError}}%</td></tr>
+{{range .Report.SectionsWithOverlay}}{{if .Statistics}}<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{printf "%.2f" .Statistics.ChiSquare}}</td><td>{{printf "%.5f" .Statistics.Mean}}</td><td>{{printf "%.6f" .Statistics.SerialCorrelation}}</td><td>{{printf "%.2f" .Statistics.MonteCarloPiError}}%</td></tr>
+{{end}}{{end}}</table>
+{{end}}{{if .Report.Metrics}}<h2>Metrics</h2>
+<table>
+<tr><th>Name</th><th>Kind</th><th>Conditional Entropy</th><th>Rényi Entropy</th><th>Min-Entropy</th><th>Compression Ratio</th></tr>
+<tr><td>{{.Report.File}}</td><td>file</td><td>{{printf "%.5f" .Report.Metrics.ConditionalEntropy}}</td><td>{{printf "%.5f" .Report.Metrics.RenyiEntropy}}</td><td>{{printf "%.5f" .Report.Metrics.MinEntropy}}</td><td>{{printf "%.4f" .Report.Metrics.CompressionRatio}}</td></tr>
+{{range .Report.SectionsWithOverlay}}{{if .Metrics}}<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{printf "%.5f" .Metrics.ConditionalEntropy}}</td><td>{{printf "%.5f" .Metrics.RenyiEntropy}}</td><td>{{printf "%.5f" .Metrics.MinEntropy}}</td><td>{{printf "%.4f" .Metrics.CompressionRatio}}</td></tr>
+{{end}}{{end}}</table>
+{{end}}{{if .Report.Resources}}<h2>Resources</h2>
+<table>
+<tr><th>Resource</th><th>Offset</th><th>Size</th><th>Embedded</th><th>Entropy</th></tr>
+{{range .Report.Resources}}<tr><td>{{.Path}}</td><td>{{printf "0x%08x" .Offset}}</td><td>{{.Size}}</td><td>{{.Magic}}</td><td class="{{entropyClass .Entropy}}">{{printf "%.5f" .Entropy}}</td></tr>
+{{end}}</table>
+{{end}}<h2>Charts</h2>
+{{range .Charts}}<img alt="chart" src="{{.}}">
+{{end}}{{end}}</body>
+</html>
+`))
+
+// HTMLWriter struct
+type HTMLWriter struct{}
+
+// Write function
+// Write renders every report into one self-contained HTML page with SVG charts.
+func (HTMLWriter) Write(w io.Writer, reports []Report) error {
+	type htmlReport struct {
+		Report Report
+		Charts []template.URL
+	}
+
+	var pages []htmlReport
+	for _, report := range reports {
+		// Call function named buildCharts
+		charts, err := buildCharts(report)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, htmlReport{report, charts})
+	}
+
+	return htmlTemplate.Execute(w, struct {
+		Reports []htmlReport
+	}{pages})
+}
+
+// buildCharts function
+// buildCharts renders the section, profile and histogram charts as SVG data URIs.
+func buildCharts(report Report) ([]template.URL, error) {
+	var plots []*plot.Plot
+
+	// Section entropy bars, files without section headers have none
+	var names []string
+	var entropies []float64
+	for _, section := range report.SectionsOfKind(Calculate.KindSection) {
+		names = append(names, section.Name)
+		entropies = append(entropies, section.Entropy)
+	}
+	if len(names) > 0 {
+		p, err := Graph.SectionEntropy("Sections Entropy", names, entropies, Colors.EntropyThreshold)
+		if err != nil {
+			return nil, err
+		}
+		plots = append(plots, p)
+	}
+
+	// Entropy over file offset
+	if len(report.Profile) > 0 {
+		var profile []Calculate.WindowEntropy
+		for _, window := range report.Profile {
+			profile = append(profile, Calculate.WindowEntropy{
+				Offset:  window.Offset,
+				Size:    window.Size,
+				Entropy: window.Entropy,
+				Region:  window.Region,
+			})
+		}
+
+		var regions []Calculate.Region
+		for _, region := range report.Regions {
+			regions = append(regions, Calculate.Region{
+				Name:   region.Name,
+				Kind:   region.Kind,
+				Offset: region.Offset,
+				Size:   region.Size,
+			})
+		}
+
+		p, err := Graph.EntropyProfile("Entropy Profile", profile, regions, Colors.EntropyThreshold)
+		if err != nil {
+			return nil, err
+		}
+		plots = append(plots, p)
+	}
+
+	// Byte histogram
+	histogram := Calculate.Histogram(report.Histogram)
+	p, err := Graph.ByteHistogram("Byte Histogram", &histogram)
+	if err != nil {
+		return nil, err
+	}
+	plots = append(plots, p)
+
+	// Render every chart as an inline SVG image
+	var charts []template.URL
+	for _, p := range plots {
+		svg, err := Graph.RenderSVG(p, 10*vg.Inch, 5*vg.Inch)
+		if err != nil {
+			return nil, err
+		}
+		charts = append(charts, template.URL(fmt.Sprintf("data:image/svg+xml;base64,%s", base64.StdEncoding.EncodeToString(svg))))
+	}
+
+	return charts, nil
+}
@@ -1,41 +1,315 @@
 package Output
 
 import (
+	"SugarFree/Packages/Calculate"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// ReportSchemaVersion is bumped whenever the JSON report layout changes
+const ReportSchemaVersion = 1
+
 // Section struct
 type Section struct {
-	Name    string
-	Entropy float64
+	Name            string      `json:"name"`
+	Kind            string      `json:"kind"`
+	Offset          int64       `json:"offset"`
+	RawSize         int64       `json:"raw_size"`
+	VirtualSize     int64       `json:"virtual_size"`
+	Characteristics uint32      `json:"characteristics"`
+	Entropy         float64     `json:"entropy"`
+	Statistics      *Statistics `json:"statistics,omitempty"`
+	Metrics         *Metrics    `json:"metrics,omitempty"`
+}
+
+// Metrics struct
+type Metrics struct {
+	ConditionalEntropy float64 `json:"conditional_entropy"`
+	RenyiEntropy       float64 `json:"renyi_entropy"`
+	MinEntropy         float64 `json:"min_entropy"`
+	CompressionRatio   float64 `json:"compression_ratio"`
+}
+
+// NewMetrics function
+// NewMetrics converts the metrics computed by the Calculate package.
+func NewMetrics(metrics Calculate.Metrics) *Metrics {
+	return &Metrics{
+		ConditionalEntropy: metrics.ConditionalEntropy,
+		RenyiEntropy:       metrics.RenyiEntropy,
+		MinEntropy:         metrics.MinEntropy,
+		CompressionRatio:   metrics.CompressionRatio,
+	}
+}
+
+// Statistics struct
+type Statistics struct {
+	Histogram         [256]uint64 `json:"histogram"`
+	ChiSquare         float64     `json:"chi_square"`
+	Mean              float64     `json:"mean"`
+	SerialCorrelation float64     `json:"serial_correlation"`
+	MonteCarloPi      float64     `json:"monte_carlo_pi"`
+	MonteCarloPiError float64     `json:"monte_carlo_pi_error"`
+}
+
+// NewStatistics function
+// NewStatistics converts the statistics computed by the Calculate package.
+func NewStatistics(statistics Calculate.Statistics) *Statistics {
+	return &Statistics{
+		Histogram:         statistics.Histogram,
+		ChiSquare:         statistics.ChiSquare,
+		Mean:              statistics.Mean,
+		SerialCorrelation: statistics.SerialCorrelation,
+		MonteCarloPi:      statistics.MonteCarloPi,
+		MonteCarloPiError: statistics.MonteCarloPiError,
+	}
+}
+
+// Hashes struct
+type Hashes struct {
+	MD5    string `json:"md5"`
+	SHA1   string `json:"sha1"`
+	SHA256 string `json:"sha256"`
+}
+
+// Region struct
+type Region struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
 }
 
-// Write2File function
-func Write2File(sections []Section, filePath string, fileName string, fileSize float64, fullEntropy float64, getDateTime string) {
+// Resource struct
+type Resource struct {
+	Type     string  `json:"type"`
+	Name     string  `json:"name"`
+	Language string  `json:"language"`
+	Offset   int64   `json:"offset"`
+	Size     int64   `json:"size"`
+	Entropy  float64 `json:"entropy"`
+	Magic    string  `json:"magic,omitempty"`
+}
+
+// Path function
+// Path returns the type, name and language of a resource joined by slashes.
+func (r Resource) Path() string {
+	return r.Type + "/" + r.Name + "/" + r.Language
+}
+
+// Report struct
+type Report struct {
+	GeneratedAt     string      `json:"generated_at"`
+	File            string      `json:"file"`
+	Format          string      `json:"format"`
+	Hashes          Hashes      `json:"hashes"`
+	Size            int64       `json:"size"`
+	Entropy         float64     `json:"entropy"`
+	OverlayExcluded bool        `json:"overlay_excluded"`
+	Sections        []Section   `json:"sections"`
+	PseudoRegions   []Section   `json:"pseudo_regions,omitempty"`
+	Overlay         *Section    `json:"overlay,omitempty"`
+	Resources       []Resource  `json:"resources,omitempty"`
+	Statistics      *Statistics `json:"statistics,omitempty"`
+	Metrics         *Metrics    `json:"metrics,omitempty"`
+	Regions         []Region    `json:"regions,omitempty"`
+	Profile         []Window    `json:"profile,omitempty"`
+	Histogram       [256]uint64 `json:"-"`
+}
+
+// Window struct
+type Window struct {
+	Offset  int64   `json:"offset"`
+	Size    int64   `json:"size"`
+	Entropy float64 `json:"entropy"`
+	Region  string  `json:"region"`
+}
+
+// MaxSection function
+// MaxSection returns the section with the highest entropy, if any.
+func (r Report) MaxSection() (Section, bool) {
+	var maxSection Section
+	found := false
+	for _, section := range r.Sections {
+		if section.Kind != Calculate.KindSection {
+			continue
+		}
+		if !found || section.Entropy > maxSection.Entropy {
+			maxSection = section
+			found = true
+		}
+	}
+
+	return maxSection, found
+}
+
+// AllSections function
+// AllSections returns the sections followed by the pseudo-regions.
+func (r Report) AllSections() []Section {
+	return append(r.Sections[:len(r.Sections):len(r.Sections)], r.PseudoRegions...)
+}
+
+// OverlayShare function
+// OverlayShare returns the percentage of the file taken by the overlay.
+func (r Report) OverlayShare() float64 {
+	if r.Overlay == nil || r.Size == 0 {
+		return 0
+	}
+
+	return float64(r.Overlay.RawSize) / float64(r.Size) * 100
+}
+
+// Coverage function
+// Coverage returns the number of bytes covered by the sections, headers,
+// gaps and overlay, which equals the file size when nothing is missed.
+func (r Report) Coverage() int64 {
+	var covered int64
+	for _, section := range r.AllSections() {
+		switch section.Kind {
+		case Calculate.KindSection, Calculate.KindHeader, Calculate.KindGap:
+			covered += section.RawSize
+		}
+	}
+	if r.Overlay != nil {
+		covered += r.Overlay.RawSize
+	}
+
+	return covered
+}
+
+// SectionsWithOverlay function
+// SectionsWithOverlay returns the sections and pseudo-regions in report
+// order followed by the overlay.
+func (r Report) SectionsWithOverlay() []Section {
+	if r.Overlay == nil {
+		return r.AllSections()
+	}
+
+	return append(r.AllSections(), *r.Overlay)
+}
+
+// OtherKinds function
+// OtherKinds returns the kinds other than sections in order of appearance.
+func (r Report) OtherKinds() []string {
+	var kinds []string
+	seen := map[string]bool{Calculate.KindSection: true}
+	for _, section := range r.AllSections() {
+		if !seen[section.Kind] {
+			seen[section.Kind] = true
+			kinds = append(kinds, section.Kind)
+		}
+	}
+
+	return kinds
+}
+
+// KindTitle function
+// KindTitle returns the plural heading used for a kind.
+func KindTitle(kind string) string {
+	if kind == "" {
+		return "Regions"
+	}
+
+	words := strings.Fields(kind)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	return strings.Join(words, " ") + "s"
+}
+
+// SectionsOfKind function
+// SectionsOfKind returns the sections of the given kind in report order.
+func (r Report) SectionsOfKind(kind string) []Section {
+	var sections []Section
+	for _, section := range r.AllSections() {
+		if section.Kind == kind {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+// ReportWriter interface
+// ReportWriter renders one or more reports in a single output format.
+type ReportWriter interface {
+	Write(w io.Writer, reports []Report) error
+}
+
+// reportWriters maps every supported format name to its writer
+var reportWriters = map[string]ReportWriter{
+	"text":     TextWriter{},
+	"json":     JSONWriter{},
+	"csv":      CSVWriter{},
+	"html":     HTMLWriter{},
+	"markdown": MarkdownWriter{},
+	"md":       MarkdownWriter{},
+}
+
+// NewReportWriter function
+// NewReportWriter returns the writer registered for format.
+func NewReportWriter(format string) (ReportWriter, error) {
+	writer, ok := reportWriters[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("invalid format %q, valid formats are: %s", format, strings.Join(Formats(), ", "))
+	}
+
+	return writer, nil
+}
+
+// Formats function
+// Formats returns the names of all supported report formats.
+func Formats() []string {
+	var formats []string
+	for format := range reportWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// WriteReportFile function
+// WriteReportFile renders the reports with writer into a new file.
+func WriteReportFile(writer ReportWriter, reports []Report, filePath string) error {
 	file, err := os.Create(filePath)
 	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
+		return fmt.Errorf("failed to create file: %v", err)
 	}
-	defer file.Close()
 
-	WriteBasicInfo(file, fileName, fileSize, fullEntropy, getDateTime)
-	WriteSectionInfo(file, sections)
-}
+	if err := writer.Write(file, reports); err != nil {
+		file.Close()
+		return err
+	}
 
-// writeBasicInfo writes basic file information
-func WriteBasicInfo(file *os.File, fileName string, fileSize float64, entropy float64, getDateTime string) {
-	fmt.Fprintf(file, "PE Analysis Report - %s\n\n", getDateTime)
-	fmt.Fprintf(file, "File Name: %s\n", fileName)
-	fmt.Fprintf(file, "File Size: %f bytes\n", fileSize)
-	fmt.Fprintf(file, "Overall PE Entropy: %.5f\n", entropy)
+	return file.Close()
 }
 
-// writeSectionInfo writes detailed section information
-func WriteSectionInfo(file *os.File, sections []Section) {
-	fmt.Fprintln(file, "\nPE Sections Entropy:")
-	for _, section := range sections {
-		fmt.Fprintf(file, "  >>> \"%s\" Entropy: %.5f\n", section.Name, section.Entropy)
+// WriteProfileCSV function
+// WriteProfileCSV exports a windowed entropy profile as CSV.
+func WriteProfileCSV(windows []Window, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %v", err)
 	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"offset", "size", "entropy", "region"})
+	for _, window := range windows {
+		writer.Write([]string{
+			strconv.FormatInt(window.Offset, 10),
+			strconv.FormatInt(window.Size, 10),
+			strconv.FormatFloat(window.Entropy, 'f', 5, 64),
+			window.Region,
+		})
+	}
+	writer.Flush()
+
+	return writer.Error()
 }
@@ -0,0 +1,328 @@
+package Output
+
+import (
+	"SugarFree/Packages/Calculate"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TextWriter struct
+type TextWriter struct{}
+
+// Write function
+// Write renders every report as a plain text summary.
+func (TextWriter) Write(w io.Writer, reports []Report) error {
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "%s Analysis Report - %s\n\n", report.Format, report.GeneratedAt)
+		fmt.Fprintf(w, "File Name: %s\n", report.File)
+		fmt.Fprintf(w, "File Size: %d bytes (%.2f KB)\n", report.Size, float64(report.Size)/1024.0)
+		fmt.Fprintf(w, "MD5: %s\n", report.Hashes.MD5)
+		fmt.Fprintf(w, "SHA1: %s\n", report.Hashes.SHA1)
+		fmt.Fprintf(w, "SHA256: %s\n", report.Hashes.SHA256)
+		fmt.Fprintf(w, "Overall %s Entropy: %.5f%s\n", report.Format, report.Entropy, overlayScope(report))
+		if report.Overlay != nil {
+			fmt.Fprintf(w, "Overlay: offset 0x%08x, size %d bytes (%.2f%%), entropy %.5f%s%s\n", report.Overlay.Offset, report.Overlay.RawSize, report.OverlayShare(), report.Overlay.Entropy, statisticsNote(report.Overlay.Statistics), metricsNote(report.Overlay.Metrics))
+		}
+		if report.Statistics != nil {
+			fmt.Fprintf(w, "Statistics:%s\n", statisticsNote(report.Statistics))
+		}
+		if report.Metrics != nil {
+			fmt.Fprintf(w, "Metrics:%s\n", metricsNote(report.Metrics))
+		}
+
+		fmt.Fprintf(w, "\n%s Sections Entropy:\n", report.Format)
+		for _, section := range report.SectionsOfKind(Calculate.KindSection) {
+			fmt.Fprintf(w, "  >>> \"%s\" Entropy: %.5f%s%s\n", section.Name, section.Entropy, statisticsNote(section.Statistics), metricsNote(section.Metrics))
+		}
+
+		for _, kind := range report.OtherKinds() {
+			fmt.Fprintf(w, "\n%s %s Entropy:\n", report.Format, KindTitle(kind))
+			for _, section := range report.SectionsOfKind(kind) {
+				fmt.Fprintf(w, "  >>> \"%s\" Entropy: %.5f%s%s\n", section.Name, section.Entropy, statisticsNote(section.Statistics), metricsNote(section.Metrics))
+			}
+		}
+
+		if len(report.Resources) > 0 {
+			fmt.Fprintf(w, "\n%s Resources Entropy:\n", report.Format)
+			for _, resource := range report.Resources {
+				fmt.Fprintf(w, "  >>> \"%s\" Entropy: %.5f (offset 0x%08x, %d bytes)%s\n", resource.Path(), resource.Entropy, resource.Offset, resource.Size, embeddedNote(resource))
+			}
+		}
+	}
+
+	return nil
+}
+
+// JSONWriter struct
+type JSONWriter struct{}
+
+// Write function
+// Write encodes the reports as an indented JSON document holding the schema
+// version and an array of reports, even for a single report.
+func (JSONWriter) Write(w io.Writer, reports []Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	// The document has the same shape for any number of reports
+	if reports == nil {
+		reports = []Report{}
+	}
+
+	return encoder.Encode(struct {
+		SchemaVersion int      `json:"schema_version"`
+		Reports       []Report `json:"reports"`
+	}{ReportSchemaVersion, reports})
+}
+
+// CSVWriter struct
+type CSVWriter struct{}
+
+// Write function
+// Write renders one CSV row per section, overlay and resource of every report.
+func (CSVWriter) Write(w io.Writer, reports []Report) error {
+	writer := csv.NewWriter(w)
+	header := []string{"file", "format", "kind", "name", "offset", "raw_size", "virtual_size", "characteristics", "entropy"}
+
+	// Statistics and metrics columns are only added when they were computed
+	withStatistics := false
+	for _, report := range reports {
+		withStatistics = withStatistics || report.Statistics != nil
+	}
+	withMetrics := false
+	for _, report := range reports {
+		withMetrics = withMetrics || report.Metrics != nil
+	}
+	if withStatistics {
+		header = append(header, "chi_square", "mean", "serial_correlation", "monte_carlo_pi_error")
+	}
+	if withMetrics {
+		header = append(header, "conditional_entropy", "renyi_entropy", "min_entropy", "compression_ratio")
+	}
+	writer.Write(header)
+	for _, report := range reports {
+		for _, section := range report.SectionsWithOverlay() {
+			row := []string{
+				report.File,
+				report.Format,
+				section.Kind,
+				section.Name,
+				strconv.FormatInt(section.Offset, 10),
+				strconv.FormatInt(section.RawSize, 10),
+				strconv.FormatInt(section.VirtualSize, 10),
+				fmt.Sprintf("0x%08x", section.Characteristics),
+				strconv.FormatFloat(section.Entropy, 'f', 5, 64),
+			}
+			if withStatistics {
+				row = append(row, statisticsColumns(section.Statistics)...)
+			}
+			if withMetrics {
+				row = append(row, metricsColumns(section.Metrics)...)
+			}
+			writer.Write(row)
+		}
+
+		for _, resource := range report.Resources {
+			row := []string{
+				report.File,
+				report.Format,
+				"resource",
+				resource.Path(),
+				strconv.FormatInt(resource.Offset, 10),
+				strconv.FormatInt(resource.Size, 10),
+				"0",
+				"0x00000000",
+				strconv.FormatFloat(resource.Entropy, 'f', 5, 64),
+			}
+			if withStatistics {
+				row = append(row, statisticsColumns(nil)...)
+			}
+			if withMetrics {
+				row = append(row, metricsColumns(nil)...)
+			}
+			writer.Write(row)
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// MarkdownWriter struct
+type MarkdownWriter struct{}
+
+// Write function
+// Write renders every report as a Markdown summary and section table.
+func (MarkdownWriter) Write(w io.Writer, reports []Report) error {
+	for _, report := range reports {
+		fmt.Fprintf(w, "## %s Analysis Report - %s\n\n", report.Format, markdownEscape(report.File))
+		fmt.Fprintf(w, "- **Generated:** %s\n", report.GeneratedAt)
+		fmt.Fprintf(w, "- **File Size:** %d bytes\n", report.Size)
+		fmt.Fprintf(w, "- **SHA256:** `%s`\n", report.Hashes.SHA256)
+		fmt.Fprintf(w, "- **Overall %s Entropy:** %.5f%s\n", report.Format, report.Entropy, overlayScope(report))
+		if report.Overlay != nil {
+			fmt.Fprintf(w, "- **Overlay:** offset 0x%08x, %d bytes (%.2f%%), entropy %.5f\n", report.Overlay.Offset, report.Overlay.RawSize, report.OverlayShare(), report.Overlay.Entropy)
+		}
+		if report.Statistics != nil {
+			fmt.Fprintf(w, "- **Statistics:**%s\n", statisticsNote(report.Statistics))
+		}
+		if report.Metrics != nil {
+			fmt.Fprintf(w, "- **Metrics:**%s\n", metricsNote(report.Metrics))
+		}
+		fmt.Fprintln(w)
+
+		fmt.Fprintln(w, "| Name | Kind | Offset | Raw Size | Virtual Size | Characteristics | Entropy |")
+		fmt.Fprintln(w, "|---|---|---:|---:|---:|---:|---:|")
+		for _, section := range report.AllSections() {
+			fmt.Fprintf(w, "| %s | %s | 0x%08x | %d | %d | 0x%08x | %.5f |\n",
+				markdownEscape(section.Name),
+				section.Kind,
+				section.Offset,
+				section.RawSize,
+				section.VirtualSize,
+				section.Characteristics,
+				section.Entropy)
+		}
+
+		if report.Statistics != nil {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "| Name | Kind | Chi-Square | Mean | Serial Correlation | Pi Error |")
+			fmt.Fprintln(w, "|---|---|---:|---:|---:|---:|")
+			for _, section := range report.SectionsWithOverlay() {
+				if section.Statistics == nil {
+					continue
+				}
+				fmt.Fprintf(w, "| %s | %s | %.2f | %.5f | %.6f | %.2f%% |\n",
+					markdownEscape(section.Name),
+					section.Kind,
+					section.Statistics.ChiSquare,
+					section.Statistics.Mean,
+					section.Statistics.SerialCorrelation,
+					section.Statistics.MonteCarloPiError)
+			}
+		}
+
+		if report.Metrics != nil {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "| Name | Kind | Conditional Entropy | Rényi Entropy | Min-Entropy | Compression Ratio |")
+			fmt.Fprintln(w, "|---|---|---:|---:|---:|---:|")
+			for _, section := range report.SectionsWithOverlay() {
+				if section.Metrics == nil {
+					continue
+				}
+				fmt.Fprintf(w, "| %s | %s | %.5f | %.5f | %.5f | %.4f |\n",
+					markdownEscape(section.Name),
+					section.Kind,
+					section.Metrics.ConditionalEntropy,
+					section.Metrics.RenyiEntropy,
+					section.Metrics.MinEntropy,
+					section.Metrics.CompressionRatio)
+			}
+		}
+
+		if len(report.Resources) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "| Resource | Offset | Size | Embedded | Entropy |")
+			fmt.Fprintln(w, "|---|---:|---:|---|---:|")
+			for _, resource := range report.Resources {
+				fmt.Fprintf(w, "| %s | 0x%08x | %d | %s | %.5f |\n",
+					markdownEscape(resource.Path()),
+					resource.Offset,
+					resource.Size,
+					resource.Magic,
+					resource.Entropy)
+			}
+		}
+
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// statisticsColumns function
+// statisticsColumns formats the statistics of a region as CSV cells.
+func statisticsColumns(statistics *Statistics) []string {
+	if statistics == nil {
+		return []string{"", "", "", ""}
+	}
+
+	return []string{
+		strconv.FormatFloat(statistics.ChiSquare, 'f', 2, 64),
+		strconv.FormatFloat(statistics.Mean, 'f', 5, 64),
+		strconv.FormatFloat(statistics.SerialCorrelation, 'f', 6, 64),
+		strconv.FormatFloat(statistics.MonteCarloPiError, 'f', 2, 64),
+	}
+}
+
+// metricsColumns function
+// metricsColumns formats the metrics of a region as CSV cells.
+func metricsColumns(metrics *Metrics) []string {
+	if metrics == nil {
+		return []string{"", "", "", ""}
+	}
+
+	return []string{
+		strconv.FormatFloat(metrics.ConditionalEntropy, 'f', 5, 64),
+		strconv.FormatFloat(metrics.RenyiEntropy, 'f', 5, 64),
+		strconv.FormatFloat(metrics.MinEntropy, 'f', 5, 64),
+		strconv.FormatFloat(metrics.CompressionRatio, 'f', 4, 64),
+	}
+}
+
+// metricsNote function
+// metricsNote summarizes the metrics of a region on one line.
+func metricsNote(metrics *Metrics) string {
+	if metrics == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" (conditional %.5f, renyi %.5f, min-entropy %.5f, compression ratio %.4f)",
+		metrics.ConditionalEntropy, metrics.RenyiEntropy, metrics.MinEntropy, metrics.CompressionRatio)
+}
+
+// statisticsNote function
+// statisticsNote summarizes the statistics of a region on one line.
+func statisticsNote(statistics *Statistics) string {
+	if statistics == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" (chi-square %.2f, mean %.5f, serial correlation %.6f, pi error %.2f%%)",
+		statistics.ChiSquare, statistics.Mean, statistics.SerialCorrelation, statistics.MonteCarloPiError)
+}
+
+// embeddedNote function
+// embeddedNote flags a resource that starts with an executable or archive magic.
+func embeddedNote(resource Resource) string {
+	if resource.Magic == "" {
+		return ""
+	}
+
+	return " [embedded " + resource.Magic + "]"
+}
+
+// overlayScope function
+// overlayScope notes when the overall entropy leaves the overlay out.
+func overlayScope(report Report) string {
+	if report.OverlayExcluded && report.Overlay != nil {
+		return " (excluding overlay)"
+	}
+
+	return ""
+}
+
+// markdownEscape function
+// markdownEscape escapes characters that would break a Markdown table cell.
+func markdownEscape(value string) string {
+	return strings.NewReplacer("|", "\\|", "_", "\\_", "*", "\\*", "`", "\\`").Replace(value)
+}
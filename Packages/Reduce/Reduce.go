@@ -2,35 +2,74 @@ package Reduce
 
 import (
 	"SugarFree/Packages/WordList"
-	"log"
+	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
 )
 
-// ApplyStrategy function
-func ApplyStrategy(binaryData []byte, number int, strategy string) []byte {
-	switch strings.ToLower(strategy) {
-	case "zero":
-		zeroBytes := make([]byte, number)
-		//fmt.Println(zeroBytes)
+// Staged reduction limits
+const (
+	StageSize = 60000 // Padding bytes appended by every reduction stage
+	MaxStages = 10    // Maximum number of stages to prevent infinite loops
+)
 
-		result := make([]byte, len(binaryData)+number)
-		copy(result, binaryData)
-		copy(result[len(binaryData):], zeroBytes)
+// Strategy interface
+// Strategy generates the padding appended to lower the entropy of a file.
+type Strategy interface {
+	Name() string                          // Name used to select the strategy
+	Description() string                   // One line description for the strategy list
+	Generate(n int, rng *rand.Rand) []byte // Exactly n bytes of padding
+	Distribution() [256]float64            // Expected byte distribution of the padding
+}
 
-		return result
-	case "word":
-		// Call function named SelectWords
-		words := WordList.SelectWords(number)
-		//fmt.Print(words)
+// strategies maps every registered strategy name to its strategy
+var strategies = map[string]Strategy{}
 
-		wordsBytes := []byte(strings.Join(words, ""))
-		//fmt.Print(wordsBytes)
+// init function
+func init() {
+	RegisterStrategy(zeroStrategy{})
+	RegisterStrategy(wordStrategy{words: WordList.DefaultWords()})
+	RegisterStrategy(&patternStrategy{pattern: defaultPattern})
+}
 
-		result := append(binaryData, wordsBytes...)
+// RegisterStrategy function
+// RegisterStrategy makes a strategy selectable by its name, replacing any
+// strategy registered under the same name.
+func RegisterStrategy(strategy Strategy) {
+	strategies[strings.ToLower(strategy.Name())] = strategy
+}
 
-		return result
-	default:
-		log.Fatal("Error: Invalid strategy provided. Please provide a valid strategy to continue...\n\n")
-		return nil
+// NewStrategy function
+// NewStrategy returns the strategy registered for name.
+func NewStrategy(name string) (Strategy, error) {
+	strategy, ok := strategies[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("invalid strategy %q, valid strategies are: %s", name, strings.Join(Names(), ", "))
 	}
+
+	return strategy, nil
+}
+
+// Names function
+// Names returns the names of all registered strategies.
+func Names() []string {
+	var names []string
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Strategies function
+// Strategies returns all registered strategies sorted by name.
+func Strategies() []Strategy {
+	var list []Strategy
+	for _, name := range Names() {
+		list = append(list, strategies[name])
+	}
+
+	return list
 }
@@ -0,0 +1,153 @@
+package Reduce
+
+import (
+	"SugarFree/Packages/WordList"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// defaultPattern is the fill of the pattern strategy when none is set
+var defaultPattern = []byte{0x90}
+
+// zeroStrategy appends null bytes
+type zeroStrategy struct{}
+
+// Name function
+func (zeroStrategy) Name() string {
+	return "zero"
+}
+
+// Description function
+func (zeroStrategy) Description() string {
+	return "Append null bytes"
+}
+
+// Generate function
+func (zeroStrategy) Generate(n int, rng *rand.Rand) []byte {
+	return make([]byte, n)
+}
+
+// Distribution function
+func (zeroStrategy) Distribution() [256]float64 {
+	var distribution [256]float64
+	distribution[0] = 1
+
+	return distribution
+}
+
+// wordStrategy appends words drawn from a word list
+type wordStrategy struct {
+	words     []string
+	separator string // Text placed after every word
+}
+
+// NewWordStrategy function
+// NewWordStrategy returns a strategy that fills the padding with words drawn
+// from words, each followed by separator.
+func NewWordStrategy(words []string, separator string) (Strategy, error) {
+	if len(words) == 0 {
+		return nil, fmt.Errorf("word list is empty")
+	}
+
+	return wordStrategy{words: words, separator: separator}, nil
+}
+
+// Name function
+func (s wordStrategy) Name() string {
+	return "word"
+}
+
+// Description function
+func (s wordStrategy) Description() string {
+	return fmt.Sprintf("Append words from a word list, %d embedded English words by default", len(WordList.DefaultWords()))
+}
+
+// Generate function
+func (s wordStrategy) Generate(n int, rng *rand.Rand) []byte {
+	padding := make([]byte, 0, n)
+	for len(padding) < n {
+		// Words average around seven letters, so request enough to fill the gap
+		for _, word := range WordList.SelectWords(s.words, (n-len(padding))/4+1, rng) {
+			padding = append(padding, word...)
+			padding = append(padding, s.separator...)
+		}
+	}
+
+	return padding[:n]
+}
+
+// Distribution function
+// Distribution returns the expected byte distribution of the words and separators.
+func (s wordStrategy) Distribution() [256]float64 {
+	return WordList.Distribution(s.words, s.separator)
+}
+
+// patternStrategy appends a repeating byte pattern
+type patternStrategy struct {
+	pattern []byte
+	offset  int // Position in the pattern where the next padding continues
+}
+
+// NewPatternStrategy function
+// NewPatternStrategy returns a strategy that fills the padding with pattern,
+// continuing the repetition across consecutive calls to Generate.
+func NewPatternStrategy(pattern []byte) (Strategy, error) {
+	if len(pattern) == 0 {
+		return nil, fmt.Errorf("fill pattern is empty")
+	}
+
+	return &patternStrategy{pattern: bytes.Clone(pattern)}, nil
+}
+
+// ParsePattern function
+// ParsePattern decodes a hex fill pattern such as "90" or "0xdeadbeef",
+// ignoring whitespace.
+func ParsePattern(pattern string) ([]byte, error) {
+	pattern = strings.Join(strings.Fields(pattern), "")
+	pattern = strings.TrimPrefix(strings.ToLower(pattern), "0x")
+
+	decoded, err := hex.DecodeString(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex pattern: %w", err)
+	}
+	if len(decoded) == 0 {
+		return nil, fmt.Errorf("fill pattern is empty")
+	}
+
+	return decoded, nil
+}
+
+// Name function
+func (s *patternStrategy) Name() string {
+	return "pattern"
+}
+
+// Description function
+func (s *patternStrategy) Description() string {
+	return fmt.Sprintf("Append a repeating byte pattern, 0x%x by default", defaultPattern)
+}
+
+// Generate function
+func (s *patternStrategy) Generate(n int, rng *rand.Rand) []byte {
+	padding := make([]byte, n)
+	for i := range padding {
+		padding[i] = s.pattern[(s.offset+i)%len(s.pattern)]
+	}
+	s.offset = (s.offset + n) % len(s.pattern)
+
+	return padding
+}
+
+// Distribution function
+// Distribution returns the exact byte distribution of one pattern repetition.
+func (s *patternStrategy) Distribution() [256]float64 {
+	var distribution [256]float64
+	for _, b := range s.pattern {
+		distribution[b] += 1 / float64(len(s.pattern))
+	}
+
+	return distribution
+}
@@ -2,6 +2,8 @@ package Utils
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -91,3 +93,139 @@ func BuildNewName(name, extension, additionalName string) string {
 	// Build the new name: name_additionalName.extension
 	return fmt.Sprintf("%s_%s%s", name, additionalName, ext)
 }
+
+// WritePaddedFile function
+// WritePaddedFile streams the input file into the output file and appends
+// padding. The data is written to a temporary file next to the output that
+// replaces it only when complete, so the output may be the input itself.
+func WritePaddedFile(outputPath string, inputPath string, padding io.Reader) error {
+	// Call function named CreatePaddedFile
+	tempPath, err := CreatePaddedFile(filepath.Dir(outputPath), inputPath, padding)
+	if err != nil {
+		return err
+	}
+
+	// Replace the output with the complete file
+	if err := os.Rename(tempPath, outputPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename file: %v", err)
+	}
+
+	return nil
+}
+
+// CreatePaddedFile function
+// CreatePaddedFile streams the input file and the padding into a new
+// temporary file in directory and returns its path, so callers can name the
+// file after its content once it is complete.
+func CreatePaddedFile(directory string, inputPath string, padding io.Reader) (string, error) {
+	// Open the input file
+	input, err := os.Open(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer input.Close()
+
+	// Create the temporary file
+	output, err := os.CreateTemp(directory, ".sugarfree-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %v", err)
+	}
+	tempPath := output.Name()
+
+	// Copy the original data and append the padding
+	if _, err := io.Copy(output, input); err != nil {
+		output.Close()
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to copy file: %v", err)
+	}
+	if _, err := io.Copy(output, padding); err != nil {
+		output.Close()
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to write padding: %v", err)
+	}
+	if err := output.Close(); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to write file: %v", err)
+	}
+
+	// Temporary files are private, use the permissions of a new file
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to set file permissions: %v", err)
+	}
+
+	return tempPath, nil
+}
+
+// ExpandInputs function
+// ExpandInputs resolves files, glob patterns and directories into a
+// deduplicated list of regular files. Directories are only descended into
+// when recursive is set, otherwise just their top-level files are used.
+func ExpandInputs(inputs []string, recursive bool) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+
+	// Add a file once, keyed by its absolute path
+	addFile := func(path string) error {
+		absolutePath, err := GetAbsolutePath(path)
+		if err != nil {
+			return err
+		}
+		if !seen[absolutePath] {
+			seen[absolutePath] = true
+			files = append(files, absolutePath)
+		}
+		return nil
+	}
+
+	for _, input := range inputs {
+		// Expand glob patterns, plain paths are kept as they are
+		matches, err := filepath.Glob(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", input, err)
+		}
+		if len(matches) == 0 {
+			if strings.ContainsAny(input, "*?[") {
+				return nil, fmt.Errorf("pattern %q matched no files", input)
+			}
+			matches = []string{input}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get file info: %v", err)
+			}
+
+			if !info.IsDir() {
+				if err := addFile(match); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			// Walk directories, stopping at the top level unless recursive
+			err = filepath.WalkDir(match, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if entry.IsDir() {
+					if path != match && !recursive {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if entry.Type().IsRegular() {
+					return addFile(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to walk directory: %v", err)
+			}
+		}
+	}
+
+	return files, nil
+}
@@ -1,56 +1,125 @@
 package WordList
 
 import (
+	_ "embed"
+	"fmt"
 	"math/rand"
+	"os"
+	"sort"
 	"strings"
 )
 
-// Predefined list of unique English words (expand as needed)
-var englishWords = []string{
-	"aaron", "abandoned", "abdomen", "aberdeen", "abilities",
-	"ability", "aboriginal", "about", "above", "abraham",
-	"absence", "absolute", "absolutely", "absorb", "abstract",
-	"absurd", "abuse", "academic", "academy", "accelerate",
-	"accent", "acceptable", "acceptance", "access", "accessible",
-	"accessories", "accident", "accommodate", "accommodation", "accompany",
-	// Add more words as needed
+// defaultWords is the embedded default dictionary, one word per line
+//
+//go:embed words.txt
+var defaultWords string
+
+// englishWords holds the unique words of the default dictionary
+var englishWords = ParseWords(defaultWords)
+
+// separators maps every separator name to the text placed after each word
+var separators = map[string]string{
+	"none":    "",
+	"space":   " ",
+	"newline": "\n",
 }
 
-// SelectWords function
-func SelectWords(numWords int) []string {
-	if numWords <= len(englishWords) {
-		// Shuffle and take the first numWords
-		rand.Shuffle(len(englishWords), func(i, j int) {
-			englishWords[i], englishWords[j] = englishWords[j], englishWords[i]
-		})
-		return englishWords[:numWords]
-	}
-
-	// If we need more words than available, generate additional random words
-	result := make([]string, len(englishWords))
-	copy(result, englishWords)
-
-	// Create a map for faster lookup
-	wordMap := make(map[string]bool)
-	for _, word := range result {
-		wordMap[word] = true
-	}
-
-	// Generate additional random words
-	chars := "abcdefghijklmnopqrstuvwxyz"
-	for len(result) < numWords {
-		wordLength := rand.Intn(7) + 4 // Random length between 4 and 10
-		var word strings.Builder
-		for i := 0; i < wordLength; i++ {
-			word.WriteByte(chars[rand.Intn(len(chars))])
-		}
+// DefaultWords function
+// DefaultWords returns the words of the embedded default dictionary.
+func DefaultWords() []string {
+	return englishWords
+}
 
-		newWord := word.String()
-		if !wordMap[newWord] {
-			result = append(result, newWord)
-			wordMap[newWord] = true
+// ParseWords function
+// ParseWords returns the unique words of a word list with one word per line,
+// skipping empty lines and lines starting with '#'.
+func ParseWords(content string) []string {
+	var words []string
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(content, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
 		}
+		seen[word] = true
+		words = append(words, word)
+	}
+
+	return words
+}
+
+// LoadWords function
+// LoadWords reads a word list file with one word per line.
+func LoadWords(filePath string) ([]string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+
+	words := ParseWords(string(content))
+	if len(words) == 0 {
+		return nil, fmt.Errorf("word list %s holds no words", filePath)
+	}
+
+	return words, nil
+}
+
+// Separator function
+// Separator returns the text placed after each word for a separator name.
+func Separator(name string) (string, error) {
+	separator, ok := separators[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("invalid separator %q, valid separators are: %s", name, strings.Join(Separators(), ", "))
+	}
+
+	return separator, nil
+}
+
+// Separators function
+// Separators returns the names of all supported separators.
+func Separators() []string {
+	var names []string
+	for name := range separators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SelectWords function
+// SelectWords returns numWords words drawn uniformly from words with rng.
+func SelectWords(words []string, numWords int, rng *rand.Rand) []string {
+	result := make([]string, numWords)
+	for i := range result {
+		result[i] = words[rng.Intn(len(words))]
 	}
 
 	return result
 }
+
+// Distribution function
+// Distribution returns the byte distribution of text built from words drawn
+// uniformly from words, each followed by separator.
+func Distribution(words []string, separator string) [256]float64 {
+	var distribution [256]float64
+
+	// Every word is equally likely, so the expected share of a byte is its
+	// count over all words divided by their total length
+	total := 0
+	for _, word := range words {
+		for _, b := range []byte(word + separator) {
+			distribution[b]++
+		}
+		total += len(word) + len(separator)
+	}
+	if total == 0 {
+		return distribution
+	}
+	for i := range distribution {
+		distribution[i] /= float64(total)
+	}
+
+	return distribution
+}